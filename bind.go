@@ -0,0 +1,301 @@
+package slide
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// binding sources, also the struct tags read by Bind
+const (
+	BindPath   = "path"
+	BindQuery  = "query"
+	BindHeader = "header"
+	BindForm   = "form"
+	BindBody   = "body"
+)
+
+// sources of tagged fields in the order they are tried
+var bindSources = []string{BindPath, BindQuery, BindHeader, BindForm}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+)
+
+// BindError value of a source could not be bound to a field
+type BindError struct {
+	// Field name of the struct field, nested fields are joined with dots
+	Field string
+	// Source one of BindPath, BindQuery, BindHeader, BindForm or BindBody
+	Source string
+	// Key name of the value in the source
+	Key string
+	Err error
+}
+
+func (e *BindError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("bind %s: %v", e.Source, e.Err)
+	}
+	return fmt.Sprintf("bind %s %q to %s: %v", e.Source, e.Key, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying cause
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode status code to respond with
+func (e *BindError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// BindErrors fields which could not be bound
+type BindErrors []*BindError
+
+func (e BindErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// StatusCode status code to respond with
+func (e BindErrors) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// Bind fills input from the request and validates it with Config.Validator
+//
+// The body is decoded into input by the decoder of its Content-Type, JSON
+// when it is missing, unknown types get ErrUnsupportedMediaType, see
+// Config.BodyDecoders. Then fields tagged with path, query, header or form
+// are set from path params, query params, headers and form values, in
+// that order, the first source having a value wins. Strings,
+// bools, numbers, time.Duration, slices, pointers and types implementing
+// encoding.TextUnmarshaler like time.Time are supported, fields without a
+// value keep their current value
+//
+//	type listInput struct {
+//		TeamID int       `path:"team"`
+//		Page   int       `query:"page"`
+//		Tags   []string  `query:"tag"`
+//		Since  time.Time `query:"since"`
+//		Tenant string    `header:"X-Tenant"`
+//	}
+//
+// JSON bodies are decoded with Config.JSONCodec, options like
+// DisallowUnknownFields and UseNumber apply to them
+//
+//	err := ctx.Bind(&input, slide.DisallowUnknownFields())
+//
+// Returns BindErrors naming each field and source that failed,
+// ValidationError when the bound input fails Config.Validator and
+// ErrBodyTooLarge when the body exceeds the body limit
+func (ctx *Ctx) Bind(input interface{}, options ...BindOption) error {
+	var o bindOptions
+	for _, option := range options {
+		option(&o)
+	}
+	if err := checkBodyLimit(ctx); err != nil {
+		return err
+	}
+	if err := ctx.bindBody(input, o); err != nil {
+		return err
+	}
+	v := reflect.ValueOf(input)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	var errs BindErrors
+	ctx.bindFields(v.Elem(), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return ctx.validate(input)
+}
+
+func (ctx *Ctx) bindBody(input interface{}, options bindOptions) error {
+	contentType := mediaType(string(ctx.RequestCtx.Request.Header.ContentType()))
+	if contentType == ApplicationForm || contentType == MultipartForm {
+		// read through form tags
+		return nil
+	}
+	body := ctx.RequestCtx.Request.Body()
+	if len(body) == 0 {
+		return nil
+	}
+	if contentType == "" {
+		// clients often leave it out for JSON
+		contentType = ApplicationJSON
+	}
+	decoder, ok := ctx.app.bodyDecoder(contentType)
+	switch {
+	case ok:
+	case isJSON(contentType):
+		decoder = func(body []byte, v interface{}) error {
+			return ctx.decodeJSON(body, v, options)
+		}
+	default:
+		return ErrUnsupportedMediaType
+	}
+	if err := decoder(body, input); err != nil {
+		bindErr := &BindError{Source: BindBody, Err: err}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			bindErr.Field = typeErr.Field
+			bindErr.Key = typeErr.Field
+		}
+		return BindErrors{bindErr}
+	}
+	return nil
+}
+
+func (ctx *Ctx) bindFields(v reflect.Value, prefix string, errs *BindErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+		// exported fields of unexported embedded structs can still be set
+		if !fieldValue.CanSet() && !(field.Anonymous && fieldValue.Kind() == reflect.Struct) {
+			continue
+		}
+		name := prefix + field.Name
+		tagged := false
+		for _, source := range bindSources {
+			if !fieldValue.CanSet() {
+				break
+			}
+			key, ok := field.Tag.Lookup(source)
+			if !ok || key == "-" {
+				continue
+			}
+			tagged = true
+			values := ctx.bindValues(source, key)
+			if len(values) == 0 {
+				continue
+			}
+			if err := setField(fieldValue, values); err != nil {
+				*errs = append(*errs, &BindError{Field: name, Source: source, Key: key, Err: err})
+			}
+			break
+		}
+		if !tagged && fieldValue.Kind() == reflect.Struct && !reflect.PtrTo(field.Type).Implements(textUnmarshalerType) {
+			if field.Anonymous {
+				ctx.bindFields(fieldValue, prefix, errs)
+			} else {
+				ctx.bindFields(fieldValue, name+".", errs)
+			}
+		}
+	}
+}
+
+func (ctx *Ctx) bindValues(source, key string) []string {
+	switch source {
+	case BindPath:
+		if value := ctx.GetParam(key); value != "" {
+			return []string{value}
+		}
+	case BindQuery:
+		return bytesToStrings(ctx.RequestCtx.QueryArgs().PeekMulti(key))
+	case BindHeader:
+		if value := ctx.RequestCtx.Request.Header.Peek(key); len(value) > 0 {
+			return []string{string(value)}
+		}
+	case BindForm:
+		if form, err := ctx.RequestCtx.MultipartForm(); err == nil {
+			return form.Value[key]
+		}
+		return bytesToStrings(ctx.RequestCtx.PostArgs().PeekMulti(key))
+	}
+	return nil
+}
+
+func bytesToStrings(values [][]byte) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = string(v)
+	}
+	return s
+}
+
+// sets field from values, all of them for slices and the first otherwise
+func setField(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Ptr {
+		ptr := reflect.New(v.Type().Elem())
+		if err := setField(ptr.Elem(), values); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(values[0]))
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setField(slice.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+	return setValue(v, values[0])
+}
+
+func setValue(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		v.SetBytes([]byte(value))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package slide
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/fr"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	frtranslations "github.com/go-playground/validator/v10/translations/fr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vmihailenco/msgpack/v4"
+)
+
+type BindSuite struct {
+	suite.Suite
+	Slide *Slide
+}
+
+type pagination struct {
+	Page    int  `query:"page"`
+	PerPage *int `query:"per_page"`
+}
+
+type bindInput struct {
+	pagination
+	TeamID  int64         `path:"team"`
+	Tags    []string      `query:"tag"`
+	Since   time.Time     `query:"since"`
+	Timeout time.Duration `query:"timeout"`
+	Active  bool          `query:"active"`
+	Tenant  string        `header:"X-Tenant"`
+	Name    string        `json:"name" form:"name"`
+	Filter  struct {
+		Score float64 `query:"score"`
+	}
+}
+
+func (suite *BindSuite) SetupTest() {
+	suite.Slide = InitServer(&Config{})
+}
+
+func (suite *BindSuite) bind(method, url, contentType, body string, headers map[string]string) (*bindInput, int, error) {
+	input := &bindInput{}
+	var bindErr error
+	suite.Slide.addRoute(method, "/teams/:team", []handler{func(ctx *Ctx) error {
+		bindErr = ctx.Bind(input)
+		return bindErr
+	}})
+	r, err := http.NewRequest(method, url, strings.NewReader(body))
+	if !assert.Nil(suite.T(), err) {
+		return nil, 0, nil
+	}
+	if contentType != "" {
+		r.Header.Set(ContentType, contentType)
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := testServer(r, suite.Slide)
+	if !assert.Nil(suite.T(), err) {
+		return nil, 0, nil
+	}
+	_, _ = ioutil.ReadAll(res.Body)
+	return input, res.StatusCode, bindErr
+}
+
+func (suite *BindSuite) TestAllSources() {
+	url := "http://test/teams/42?page=2&per_page=50&tag=a&tag=b&since=2020-06-01T10:00:00Z&timeout=1m&active=true&score=0.5"
+	input, _, err := suite.bind(POST, url, ApplicationJSON, `{"name":"slide"}`, map[string]string{"X-Tenant": "acme"})
+	if assert.Nil(suite.T(), err) {
+		assert.Equal(suite.T(), int64(42), input.TeamID)
+		assert.Equal(suite.T(), 2, input.Page)
+		if assert.NotNil(suite.T(), input.PerPage) {
+			assert.Equal(suite.T(), 50, *input.PerPage)
+		}
+		assert.Equal(suite.T(), []string{"a", "b"}, input.Tags)
+		assert.Equal(suite.T(), time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC), input.Since)
+		assert.Equal(suite.T(), time.Minute, input.Timeout)
+		assert.Equal(suite.T(), true, input.Active)
+		assert.Equal(suite.T(), "acme", input.Tenant)
+		assert.Equal(suite.T(), "slide", input.Name)
+		assert.Equal(suite.T(), 0.5, input.Filter.Score)
+	}
+}
+
+func (suite *BindSuite) TestForm() {
+	input, _, err := suite.bind(POST, "http://test/teams/1", "application/x-www-form-urlencoded", "name=slide", nil)
+	if assert.Nil(suite.T(), err) {
+		assert.Equal(suite.T(), "slide", input.Name)
+		assert.Nil(suite.T(), input.PerPage)
+	}
+}
+
+func (suite *BindSuite) TestErrors() {
+	_, status, err := suite.bind(GET, "http://test/teams/x?page=two", "", "", nil)
+	var bindErrs BindErrors
+	if assert.True(suite.T(), errors.As(err, &bindErrs)) && assert.Len(suite.T(), bindErrs, 2) {
+		// embedded fields come first
+		assert.Equal(suite.T(), "Page", bindErrs[0].Field)
+		assert.Equal(suite.T(), BindQuery, bindErrs[0].Source)
+		assert.Equal(suite.T(), "page", bindErrs[0].Key)
+		assert.Equal(suite.T(), "TeamID", bindErrs[1].Field)
+		assert.Equal(suite.T(), BindPath, bindErrs[1].Source)
+	}
+	assert.Equal(suite.T(), http.StatusBadRequest, status)
+}
+
+func (suite *BindSuite) TestBodyError() {
+	_, status, err := suite.bind(POST, "http://test/teams/1", ApplicationJSON, `{"name":1}`, nil)
+	var bindErrs BindErrors
+	if assert.True(suite.T(), errors.As(err, &bindErrs)) && assert.Len(suite.T(), bindErrs, 1) {
+		assert.Equal(suite.T(), BindBody, bindErrs[0].Source)
+		assert.Equal(suite.T(), "name", bindErrs[0].Field)
+	}
+	assert.Equal(suite.T(), http.StatusBadRequest, status)
+}
+
+func (suite *BindSuite) TestTrailingJSON() {
+	for _, body := range []string{`{"name":"slide"} garbage`, `{"name":"slide"}{"name":"other"}`, `{"name":"slide"}]`} {
+		suite.Slide = InitServer(&Config{})
+		_, status, err := suite.bind(POST, "http://test/teams/1", ApplicationJSON, body, nil)
+		var bindErrs BindErrors
+		if assert.True(suite.T(), errors.As(err, &bindErrs), body) && assert.Len(suite.T(), bindErrs, 1) {
+			assert.Equal(suite.T(), BindBody, bindErrs[0].Source)
+		}
+		assert.Equal(suite.T(), http.StatusBadRequest, status, body)
+	}
+	suite.Slide = InitServer(&Config{})
+	input, _, err := suite.bind(POST, "http://test/teams/1", ApplicationJSON, "{\"name\":\"slide\"}\n ", nil)
+	if assert.Nil(suite.T(), err) {
+		assert.Equal(suite.T(), "slide", input.Name)
+	}
+}
+
+func (suite *BindSuite) TestDecoders() {
+	msgpackBody, err := msgpack.Marshal(map[string]string{"Name": "slide"})
+	assert.Nil(suite.T(), err)
+	tests := []struct {
+		contentType string
+		body        string
+	}{
+		{"", `{"name":"slide"}`},
+		{"application/vnd.api+json; charset=utf-8", `{"name":"slide"}`},
+		{ApplicationXML, `<input><Name>slide</Name></input>`},
+		{ApplicationYAML, "name: slide"},
+		{ApplicationMsgPack, string(msgpackBody)},
+	}
+	for _, test := range tests {
+		suite.Slide = InitServer(&Config{})
+		input, _, err := suite.bind(POST, "http://test/teams/1", test.contentType, test.body, nil)
+		if assert.Nil(suite.T(), err, test.contentType) {
+			assert.Equal(suite.T(), "slide", input.Name, test.contentType)
+		}
+	}
+}
+
+func (suite *BindSuite) TestUnsupportedMediaType() {
+	_, status, err := suite.bind(POST, "http://test/teams/1", "text/csv", "name\nslide", nil)
+	assert.Equal(suite.T(), ErrUnsupportedMediaType, err)
+	assert.Equal(suite.T(), http.StatusUnsupportedMediaType, status)
+}
+
+func (suite *BindSuite) TestCustomDecoder() {
+	suite.Slide = InitServer(&Config{
+		BodyDecoders: map[string]BodyDecoder{
+			"text/plain": func(body []byte, v interface{}) error {
+				v.(*bindInput).Name = string(body)
+				return nil
+			},
+		},
+	})
+	input, _, err := suite.bind(POST, "http://test/teams/1", "text/plain", "slide", nil)
+	if assert.Nil(suite.T(), err) {
+		assert.Equal(suite.T(), "slide", input.Name)
+	}
+}
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type validationInput struct {
+	Name    string    `json:"name" validate:"required"`
+	Age     int       `json:"age" validate:"min=18"`
+	Address address   `json:"address"`
+	Items   []address `json:"items" validate:"dive"`
+}
+
+func (suite *BindSuite) validate(config *Config, body string, headers map[string]string) (*http.Response, error) {
+	suite.Slide = InitServer(config)
+	var bindErr error
+	suite.Slide.Post("/users", func(ctx *Ctx) error {
+		bindErr = ctx.Bind(&validationInput{})
+		return bindErr
+	})
+	r, err := http.NewRequest(POST, "http://test/users", strings.NewReader(body))
+	if !assert.Nil(suite.T(), err) {
+		return nil, nil
+	}
+	r.Header.Set(ContentType, ApplicationJSON)
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := testServer(r, suite.Slide)
+	assert.Nil(suite.T(), err)
+	return res, bindErr
+}
+
+func (suite *BindSuite) TestValidationError() {
+	res, err := suite.validate(&Config{Validator: validator.New()}, `{"age":16,"items":[{"city":"Pune"},{}]}`, nil)
+	var validationErr *ValidationError
+	if assert.True(suite.T(), errors.As(err, &validationErr)) && assert.Len(suite.T(), validationErr.Errors, 4) {
+		assert.Equal(suite.T(), FieldError{Field: "name", Tag: "required", Message: "Name is a required field"}, validationErr.Errors[0])
+		assert.Equal(suite.T(), FieldError{Field: "age", Tag: "min", Param: "18", Message: "Age must be 18 or greater"}, validationErr.Errors[1])
+		assert.Equal(suite.T(), "address.city", validationErr.Errors[2].Field)
+		assert.Equal(suite.T(), "items[1].city", validationErr.Errors[3].Field)
+	}
+	if res != nil {
+		assert.Equal(suite.T(), http.StatusUnprocessableEntity, res.StatusCode)
+		assert.Equal(suite.T(), ApplicationJSON, res.Header.Get(ContentType))
+		var body ValidationError
+		assert.Nil(suite.T(), json.NewDecoder(res.Body).Decode(&body))
+		assert.Len(suite.T(), body.Errors, 4)
+	}
+}
+
+func (suite *BindSuite) TestValidationTranslation() {
+	validate := validator.New()
+	english, french := en.New(), fr.New()
+	translator := ut.New(english, english, french)
+	enTrans, _ := translator.GetTranslator("en")
+	frTrans, _ := translator.GetTranslator("fr")
+	assert.Nil(suite.T(), entranslations.RegisterDefaultTranslations(validate, enTrans))
+	assert.Nil(suite.T(), frtranslations.RegisterDefaultTranslations(validate, frTrans))
+	config := &Config{Validator: validate, Translator: translator}
+	_, err := suite.validate(config, `{"age":18,"name":"slide"}`, map[string]string{HeaderAcceptLanguage: "fr-CA, fr;q=0.9, en;q=0.5"})
+	var validationErr *ValidationError
+	if assert.True(suite.T(), errors.As(err, &validationErr)) && assert.Len(suite.T(), validationErr.Errors, 1) {
+		assert.Equal(suite.T(), "address.city", validationErr.Errors[0].Field)
+		assert.Equal(suite.T(), "City est un champ obligatoire", validationErr.Errors[0].Message)
+	}
+}
+
+func TestBind(t *testing.T) {
+	suite.Run(t, new(BindSuite))
+}
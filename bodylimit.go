@@ -0,0 +1,89 @@
+package slide
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+)
+
+var (
+	// ErrBodyTooLarge request body exceeds the body limit
+	ErrBodyTooLarge = NewError(http.StatusRequestEntityTooLarge, "request body too large")
+)
+
+// SetBodyLimit sets maximum body size in bytes for the current request,
+// enforced before the route handler runs and by ctx.Body, ctx.Bind and
+// ctx.UploadFile. Limits set later override earlier ones, so a route can
+// raise or lower the limit of the app or group, 0 removes the limit
+//
+// Listen reads bodies up to Config.MaxRequestBodySize before handlers run,
+// larger ones are streamed and only read up to the limit, so a route can
+// accept bodies above Config.MaxRequestBodySize without buffering them
+// for every other route
+func (ctx *Ctx) SetBodyLimit(limit int64) {
+	ctx.bodyLimit = limit
+}
+
+// BodyLimit returns body limit of the current request, 0 if there is none
+func (ctx *Ctx) BodyLimit() int64 {
+	return ctx.bodyLimit
+}
+
+// Body returns body of the request, reading it when it is streamed.
+// Bodies larger than the body limit return ErrBodyTooLarge, middlewares
+// reading bodies before the route handler should use it instead of
+// RequestCtx.Request.Body
+func (ctx *Ctx) Body() ([]byte, error) {
+	if err := checkBodyLimit(ctx); err != nil {
+		return nil, err
+	}
+	return ctx.RequestCtx.Request.Body(), nil
+}
+
+// reads streamed bodies within the limit, streamed bodies without one
+// are bounded by Config.MaxRequestBodySize
+func checkBodyLimit(ctx *Ctx) error {
+	limit := ctx.bodyLimit
+	stream := ctx.RequestCtx.RequestBodyStream()
+	if limit <= 0 {
+		if stream == nil {
+			return nil
+		}
+		limit = fasthttp.DefaultMaxRequestBodySize
+		if ctx.app != nil && ctx.app.config.MaxRequestBodySize > 0 {
+			limit = int64(ctx.app.config.MaxRequestBodySize)
+		}
+	}
+	// declared length is checked first so the body is not read,
+	// it is -1 for chunked bodies
+	if int64(ctx.RequestCtx.Request.Header.ContentLength()) > limit {
+		return bodyTooLarge(ctx, stream)
+	}
+	if stream == nil {
+		if int64(len(ctx.RequestCtx.Request.Body())) > limit {
+			return ErrBodyTooLarge
+		}
+		return nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(stream, limit+1))
+	if err != nil {
+		ctx.RequestCtx.SetConnectionClose()
+		return ErrBadRequest.WithInternal(err)
+	}
+	if int64(len(body)) > limit {
+		return bodyTooLarge(ctx, stream)
+	}
+	// the body is read, later calls get it without the stream
+	ctx.RequestCtx.Request.SetBodyRaw(body)
+	return nil
+}
+
+func bodyTooLarge(ctx *Ctx, stream io.Reader) error {
+	if stream != nil {
+		// rest of the body is left on the connection
+		ctx.RequestCtx.SetConnectionClose()
+	}
+	return ErrBodyTooLarge
+}
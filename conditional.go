@@ -0,0 +1,133 @@
+package slide
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SetETag sets ETag of the response, tag is quoted if needed
+//
+// weak marks the tag as semantically equivalent instead of byte for byte equal
+func (ctx *Ctx) SetETag(tag string, weak bool) {
+	if !strings.HasPrefix(tag, `"`) {
+		tag = `"` + tag + `"`
+	}
+	if weak {
+		tag = "W/" + tag
+	}
+	ctx.RequestCtx.Response.Header.Set(HeaderETag, tag)
+}
+
+// SetLastModified sets Last-Modified of the response
+func (ctx *Ctx) SetLastModified(t time.Time) {
+	ctx.RequestCtx.Response.Header.Set(HeaderLastModified, t.UTC().Format(http.TimeFormat))
+}
+
+// CheckPreconditions evaluates If-Match, If-Unmodified-Since, If-None-Match and
+// If-Modified-Since against ETag and Last-Modified of the response, returns true
+// if a precondition failed and response was changed to 304 or 412
+//
+// GET and HEAD are checked after the handler, handlers changing state should set
+// validators of the current resource and call it before changing anything
+//
+//	ctx.SetETag(article.Version, false)
+//	if ctx.CheckPreconditions() {
+//		return nil
+//	}
+//
+// Reference https://tools.ietf.org/html/rfc7232#section-6
+func (ctx *Ctx) CheckPreconditions() bool {
+	ctx.preconditionsChecked = true
+	status := evaluatePreconditions(ctx)
+	if status == 0 {
+		return false
+	}
+	response := &ctx.RequestCtx.Response
+	response.ResetBody()
+	response.SetStatusCode(status)
+	if status == http.StatusNotModified {
+		response.Header.Del(ContentType)
+		response.Header.Del(HeaderContentLength)
+	}
+	return true
+}
+
+// called after route handlers, preconditions of GET and HEAD are
+// evaluated for successful responses unless the handler already did
+func applyPreconditions(ctx *Ctx) {
+	if ctx.preconditionsChecked || !(ctx.RequestCtx.IsGet() || ctx.RequestCtx.IsHead()) {
+		return
+	}
+	status := ctx.RequestCtx.Response.StatusCode()
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return
+	}
+	ctx.CheckPreconditions()
+}
+
+func evaluatePreconditions(ctx *Ctx) int {
+	request := &ctx.RequestCtx.Request.Header
+	response := &ctx.RequestCtx.Response.Header
+	etag := string(response.Peek(HeaderETag))
+	lastModified, hasLastModified := parseHTTPDate(string(response.Peek(HeaderLastModified)))
+	safe := ctx.RequestCtx.IsGet() || ctx.RequestCtx.IsHead()
+
+	if ifMatch := string(request.Peek(HeaderIfMatch)); ifMatch != "" {
+		if !matchETag(ifMatch, etag, false) {
+			return http.StatusPreconditionFailed
+		}
+	} else if ius, ok := parseHTTPDate(string(request.Peek(HeaderIfUnmodifiedSince))); ok && hasLastModified {
+		if lastModified.After(ius) {
+			return http.StatusPreconditionFailed
+		}
+	}
+	if ifNoneMatch := string(request.Peek(HeaderIfNoneMatch)); ifNoneMatch != "" {
+		if matchETag(ifNoneMatch, etag, true) {
+			if safe {
+				return http.StatusNotModified
+			}
+			return http.StatusPreconditionFailed
+		}
+	} else if ims, ok := parseHTTPDate(string(request.Peek(HeaderIfModifiedSince))); ok && safe && hasLastModified {
+		if !lastModified.After(ims) {
+			return http.StatusNotModified
+		}
+	}
+	return 0
+}
+
+// matches list of entity tags from If-Match or If-None-Match against etag,
+// weak comparison ignores the W/ prefix
+func matchETag(header, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+			continue
+		}
+		if !strings.HasPrefix(candidate, "W/") && !strings.HasPrefix(etag, "W/") && candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func parseHTTPDate(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
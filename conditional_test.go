@@ -0,0 +1,118 @@
+package slide
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConditionalSuite struct {
+	suite.Suite
+	Slide        *Slide
+	lastModified time.Time
+	updated      bool
+}
+
+func (suite *ConditionalSuite) SetupTest() {
+	app := InitServer(&Config{})
+	suite.Slide = app
+	suite.lastModified = time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	suite.updated = false
+	suite.Slide.Get("/article", func(ctx *Ctx) error {
+		ctx.SetETag("v1", false)
+		ctx.SetLastModified(suite.lastModified)
+		return ctx.Send(http.StatusOK, "article")
+	})
+	suite.Slide.Put("/article", func(ctx *Ctx) error {
+		ctx.SetETag("v1", false)
+		if ctx.CheckPreconditions() {
+			return nil
+		}
+		suite.updated = true
+		return ctx.Send(http.StatusOK, "updated")
+	})
+}
+
+func (suite *ConditionalSuite) request(method string, headers map[string]string) (*http.Response, string) {
+	r, err := http.NewRequest(method, "http://test/article", nil)
+	if !assert.Nil(suite.T(), err) {
+		return nil, ""
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := testServer(r, suite.Slide)
+	if !assert.Nil(suite.T(), err) {
+		return nil, ""
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res, string(body)
+}
+
+func (suite *ConditionalSuite) TestValidators() {
+	res, body := suite.request(GET, nil)
+	assert.Equal(suite.T(), http.StatusOK, res.StatusCode)
+	assert.Equal(suite.T(), "article", body)
+	assert.Equal(suite.T(), `"v1"`, res.Header.Get(HeaderETag))
+	assert.Equal(suite.T(), "Mon, 01 Jun 2020 10:00:00 GMT", res.Header.Get(HeaderLastModified))
+}
+
+func (suite *ConditionalSuite) TestNotModified() {
+	tests := []map[string]string{
+		{HeaderIfNoneMatch: `"v1"`},
+		{HeaderIfNoneMatch: `W/"v1", "v0"`},
+		{HeaderIfNoneMatch: "*"},
+		{HeaderIfModifiedSince: "Mon, 01 Jun 2020 10:00:00 GMT"},
+	}
+	for _, headers := range tests {
+		res, body := suite.request(GET, headers)
+		assert.Equal(suite.T(), http.StatusNotModified, res.StatusCode, headers)
+		assert.Equal(suite.T(), "", body)
+		assert.Equal(suite.T(), `"v1"`, res.Header.Get(HeaderETag))
+	}
+}
+
+func (suite *ConditionalSuite) TestModified() {
+	tests := []map[string]string{
+		{HeaderIfNoneMatch: `"v0"`},
+		{HeaderIfModifiedSince: "Sun, 31 May 2020 10:00:00 GMT"},
+		// If-None-Match takes precedence
+		{HeaderIfNoneMatch: `"v0"`, HeaderIfModifiedSince: "Mon, 01 Jun 2020 10:00:00 GMT"},
+	}
+	for _, headers := range tests {
+		res, body := suite.request(GET, headers)
+		assert.Equal(suite.T(), http.StatusOK, res.StatusCode, headers)
+		assert.Equal(suite.T(), "article", body)
+	}
+}
+
+func (suite *ConditionalSuite) TestPreconditionFailed() {
+	tests := []map[string]string{
+		{HeaderIfMatch: `"v0"`},
+		{HeaderIfMatch: `W/"v1"`},
+		{HeaderIfNoneMatch: `"v1"`},
+	}
+	for _, headers := range tests {
+		res, _ := suite.request(PUT, headers)
+		assert.Equal(suite.T(), http.StatusPreconditionFailed, res.StatusCode, headers)
+		assert.False(suite.T(), suite.updated)
+	}
+	res, _ := suite.request(GET, map[string]string{HeaderIfUnmodifiedSince: "Sun, 31 May 2020 10:00:00 GMT"})
+	assert.Equal(suite.T(), http.StatusPreconditionFailed, res.StatusCode)
+}
+
+func (suite *ConditionalSuite) TestPreconditionPassed() {
+	res, body := suite.request(PUT, map[string]string{HeaderIfMatch: `"v0", "v1"`})
+	assert.Equal(suite.T(), http.StatusOK, res.StatusCode)
+	assert.Equal(suite.T(), "updated", body)
+	assert.True(suite.T(), suite.updated)
+}
+
+func TestConditional(t *testing.T) {
+	suite.Run(t, new(ConditionalSuite))
+}
@@ -1,8 +1,58 @@
 package slide
 
-import "github.com/go-playground/validator/v10"
+import (
+	"log"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
 
 // Config -- Configuration for slide
 type Config struct {
 	Validator *validator.Validate
+	// CookieSigningKeys keys for signed cookies, first key signs
+	// and all keys are accepted when verifying so they can be rotated
+	CookieSigningKeys [][]byte
+	// CookieEncryptionKeys AES keys (16, 24 or 32 bytes) for encrypted cookies,
+	// rotated same way as CookieSigningKeys
+	CookieEncryptionKeys [][]byte
+	// MaxRequestBodySize size in bytes of request bodies Listen reads before
+	// handlers run, defaults to 4MB. Larger bodies are streamed and get 413
+	// unless the body limit of the route allows them, use ctx.SetBodyLimit
+	// or middleware.BodyLimit for other limits per route
+	MaxRequestBodySize int
+	// TrustedProxies CIDRs or addresses of proxies in front of the app,
+	// their ProxyHeader is used by ctx.IP, ctx.Scheme and ctx.Host,
+	// ex []string{"10.0.0.0/8"}
+	TrustedProxies []string
+	// ProxyHeader header TrustedProxies set with the client address, one of
+	// X-Forwarded-For, Forwarded or X-Real-IP, defaults to X-Forwarded-For.
+	// Only this header is read, scheme and host come from X-Forwarded-Proto
+	// and X-Forwarded-Host, or from proto and host of Forwarded. Headers the
+	// proxies do not set are passed on from clients and can not be trusted
+	ProxyHeader string
+	// BodyDecoders decoders used by ctx.Bind keyed by media type, ex
+	// "application/vnd.api+json", added to the built in decoders for
+	// JSON, XML, forms, MessagePack, CBOR, YAML and protobuf or replacing them.
+	// JSON is decoded with JSONCodec unless a decoder is set for it
+	BodyDecoders map[string]BodyDecoder
+	// Translator translates messages of ValidationError to locales of
+	// Accept-Language, translations have to be registered on Validator,
+	// English ones are registered when it is nil
+	Translator *ut.UniversalTranslator
+	// Production hides messages of 5xx errors from responses of the
+	// default error handler and Problems
+	Production bool
+	// ErrorLog logs internal causes of errors, messages hidden from
+	// responses and requests Listen could not read, the standard logger
+	// when nil
+	ErrorLog *log.Logger
+	// JSONCodec encodes JSON responses and decodes JSON bodies,
+	// encoding/json when nil
+	JSONCodec JSONCodec
+	// DisableJSONHTMLEscape keeps <, > and & of JSON strings in responses
+	// as they are instead of escaping them to \u003c, \u003e and \u0026
+	DisableJSONHTMLEscape bool
+	// Views renders templates of ctx.Render, see NewHTMLViews
+	Views ViewEngine
 }
@@ -1,10 +1,10 @@
 package slide
 
 import (
-	"encoding/json"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/valyala/fasthttp"
 )
@@ -13,23 +13,23 @@ import (
 type Ctx struct {
 	RequestCtx           *fasthttp.RequestCtx
 	Next                 func() error
+	app                  *Slide
 	config               *Config
 	appMiddlewareIndex   int
 	groupMiddlewareIndex int
 	routerPath           string
 	queryPath            string
+	preconditionsChecked bool
+	bodyLimit            int64
 }
 
 // JSON Sending application/json response
 func (ctx *Ctx) JSON(statusCode int, payload interface{}) error {
-	ctx.RequestCtx.Response.Header.Set(ContentType, ApplicationJSON)
-	ctx.RequestCtx.SetStatusCode(statusCode)
-	response, err := json.Marshal(payload)
+	response, err := ctx.marshalJSON(payload, "")
 	if err != nil {
 		return err
 	}
-	ctx.RequestCtx.SetBody(response)
-	return nil
+	return ctx.Blob(statusCode, ApplicationJSON, response)
 }
 
 // Send Sending a text response
@@ -78,6 +78,9 @@ func (ctx *Ctx) SendAttachment(filePath, fileName string) error {
 
 // UploadFile uploads file to given path
 func (ctx *Ctx) UploadFile(filePath, fileName string) error {
+	if err := checkBodyLimit(ctx); err != nil {
+		return err
+	}
 	form, err := ctx.RequestCtx.FormFile(fileName)
 	if err != nil {
 		return err
@@ -99,20 +102,6 @@ func (ctx *Ctx) UploadFile(filePath, fileName string) error {
 	return nil
 }
 
-// Bind Deserialize body to struct
-func (ctx *Ctx) Bind(input interface{}) error {
-	body := ctx.RequestCtx.Request.Body()
-	if err := json.Unmarshal(body, input); err != nil {
-		return err
-	}
-	if ctx.config.Validator != nil {
-		if err := ctx.config.Validator.Struct(input); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // GetParam - Getting path param
 //
 // /name/:name
@@ -159,6 +148,56 @@ func (ctx *Ctx) GetQueryParams() map[string]string {
 	return getAllQueryParams(ctx.queryPath)
 }
 
+// App returns the app serving the request
+func (ctx *Ctx) App() *Slide {
+	return ctx.app
+}
+
+// Vary adds header name to Vary of the response unless it is already
+// listed, call it when the response depends on that request header
+//
+//	ctx.Vary(slide.HeaderAcceptLanguage)
+func (ctx *Ctx) Vary(name string) {
+	header := &ctx.RequestCtx.Response.Header
+	current := string(header.Peek(HeaderVary))
+	for _, v := range strings.Split(current, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.EqualFold(v, name) {
+			return
+		}
+	}
+	if current == "" {
+		header.Set(HeaderVary, name)
+		return
+	}
+	header.Set(HeaderVary, current+", "+name)
+}
+
+// Set stores a value on the request context,
+// middlewares use it to share data with handlers
+func (ctx *Ctx) Set(key string, value interface{}) {
+	ctx.RequestCtx.SetUserValue(key, value)
+}
+
+// Get returns value stored with Set, nil if not present
+func (ctx *Ctx) Get(key string) interface{} {
+	return ctx.RequestCtx.UserValue(key)
+}
+
+// CSRFToken returns csrf token of current request set by csrf middleware,
+// embed it in forms or send it back in X-CSRF-Token header
+func (ctx *Ctx) CSRFToken() string {
+	token, _ := ctx.Get(CSRFContextKey).(string)
+	return token
+}
+
+// Nonce returns content security policy nonce of current request set by
+// secure middleware, use it as nonce attribute of inline scripts and styles
+func (ctx *Ctx) Nonce() string {
+	nonce, _ := ctx.Get(NonceContextKey).(string)
+	return nonce
+}
+
 // ServeFile serving file as response
 func (ctx *Ctx) ServeFile(filePath string) error {
 	contentType, err := getFileContentType(filePath)
@@ -172,6 +211,7 @@ func (ctx *Ctx) ServeFile(filePath string) error {
 func getRouterContext(r *fasthttp.RequestCtx, slide *Slide) *Ctx {
 	return &Ctx{
 		RequestCtx: r,
+		app:        slide,
 		config:     slide.config,
 	}
 }
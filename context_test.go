@@ -16,6 +16,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"github.com/valyala/fasthttp"
 )
 
 type ContextSuite struct {
@@ -143,10 +144,10 @@ func (suite *ContextSuite) TestServeFiles() {
 	})
 	r, err := http.NewRequest(GET, "http://test"+path, nil)
 	if assert.Nil(suite.T(), err) {
-		_, err := testServer(r, suite.Slide)
+		res, err := testServer(r, suite.Slide)
 		if assert.Nil(suite.T(), err) {
 			fileType, _ := getFileContentType(filePath)
-			assert.Equal(suite.T(), r.Header.Get(ContentType), fileType)
+			assert.Equal(suite.T(), res.Header.Get(ContentType), fileType)
 		}
 		assert.Nil(suite.T(), err)
 	}
@@ -169,6 +170,121 @@ func (suite *ContextSuite) TestSendAttachment() {
 	}
 }
 
+func (suite *ContextSuite) TestVary() {
+	path := "/hey"
+	suite.Slide.Get(path, func(ctx *Ctx) error {
+		ctx.Vary(HeaderAccept)
+		ctx.Vary("accept")
+		ctx.Vary(HeaderAcceptEncoding)
+		return ctx.Send(http.StatusOK, "")
+	})
+	r, err := http.NewRequest(GET, "http://test"+path, nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			assert.Equal(suite.T(), "Accept, Accept-Encoding", res.Header.Get(HeaderVary))
+		}
+	}
+}
+
+func (suite *ContextSuite) TestSetGet() {
+	path := "/hey"
+	suite.Slide.Get(path, func(ctx *Ctx) error {
+		return ctx.Send(http.StatusOK, ctx.Get("user").(string))
+	}, func(ctx *Ctx) error {
+		ctx.Set("user", "slide")
+		return ctx.Next()
+	})
+	r, err := http.NewRequest(GET, "http://test"+path, nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			body, err := ioutil.ReadAll(res.Body)
+			if assert.Nil(suite.T(), err) {
+				assert.Equal(suite.T(), "slide", string(body))
+			}
+		}
+	}
+}
+
+func (suite *ContextSuite) TestApp() {
+	suite.Slide.Get("/internal", func(ctx *Ctx) error {
+		return ctx.Send(http.StatusOK, "internal")
+	})
+	suite.Slide.Get("/hey", func(ctx *Ctx) error {
+		var rc fasthttp.RequestCtx
+		var req fasthttp.Request
+		req.SetRequestURI("/internal")
+		rc.Init(&req, nil, nil)
+		ctx.App().Handler()(&rc)
+		return ctx.Send(rc.Response.StatusCode(), string(rc.Response.Body()))
+	})
+	r, err := http.NewRequest(GET, "http://test/hey", nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			body, err := ioutil.ReadAll(res.Body)
+			if assert.Nil(suite.T(), err) {
+				assert.Equal(suite.T(), http.StatusOK, res.StatusCode)
+				assert.Equal(suite.T(), "internal", string(body))
+			}
+		}
+	}
+}
+
+func (suite *ContextSuite) TestCSRFToken() {
+	path := "/hey"
+	suite.Slide.Get(path, func(ctx *Ctx) error {
+		return ctx.Send(http.StatusOK, ctx.CSRFToken())
+	}, func(ctx *Ctx) error {
+		ctx.Set(CSRFContextKey, "token")
+		return ctx.Next()
+	})
+	r, err := http.NewRequest(GET, "http://test"+path, nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			body, err := ioutil.ReadAll(res.Body)
+			if assert.Nil(suite.T(), err) {
+				assert.Equal(suite.T(), "token", string(body))
+			}
+		}
+	}
+}
+
+func (suite *ContextSuite) TestNonce() {
+	path := "/hey"
+	suite.Slide.Get(path, func(ctx *Ctx) error {
+		return ctx.Send(http.StatusOK, ctx.Nonce())
+	}, func(ctx *Ctx) error {
+		ctx.Set(NonceContextKey, "nonce")
+		return ctx.Next()
+	})
+	r, err := http.NewRequest(GET, "http://test"+path, nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			body, err := ioutil.ReadAll(res.Body)
+			if assert.Nil(suite.T(), err) {
+				assert.Equal(suite.T(), "nonce", string(body))
+			}
+		}
+	}
+}
+
+func (suite *ContextSuite) TestSessionWithoutMiddleware() {
+	path := "/hey"
+	suite.Slide.Get(path, func(ctx *Ctx) error {
+		assert.Nil(suite.T(), ctx.Session())
+		return ctx.SendStatusCode(http.StatusOK)
+	})
+	r, err := http.NewRequest(GET, "http://test"+path, nil)
+	if assert.Nil(suite.T(), err) {
+		_, err := testServer(r, suite.Slide)
+		assert.Nil(suite.T(), err)
+	}
+}
+
 func createMultipartFormData(suite *ContextSuite, fieldName, filePath string) (bytes.Buffer, *multipart.Writer) {
 	var b bytes.Buffer
 	var err error
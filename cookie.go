@@ -0,0 +1,253 @@
+package slide
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// SameSite cookie attribute
+type SameSite int
+
+// ...
+const (
+	// SameSiteDefault attribute is not sent
+	SameSiteDefault SameSite = iota
+	SameSiteLax
+	SameSiteStrict
+	SameSiteNone
+)
+
+// Cookie -- response cookie
+type Cookie struct {
+	Name   string
+	Value  string
+	Path   string
+	Domain string
+	// Expires is ignored if MaxAge is set
+	Expires time.Time
+	// MaxAge in seconds, zero means not set and negative deletes the cookie
+	MaxAge   int
+	Secure   bool
+	HTTPOnly bool
+	SameSite SameSite
+	// Partitioned opts into partitioned storage (CHIPS), requires Secure
+	Partitioned bool
+}
+
+// ...
+var (
+	ErrCookieNotFound = errors.New("cookie not found")
+	ErrInvalidCookie  = errors.New("cookie is invalid")
+	ErrNoCookieKeys   = errors.New("no cookie keys configured")
+)
+
+// Cookie returns value of request cookie, empty if not present
+func (ctx *Ctx) Cookie(name string) string {
+	return string(ctx.RequestCtx.Request.Header.Cookie(name))
+}
+
+// SetCookie adds cookie to response, replacing previous cookie with same name
+func (ctx *Ctx) SetCookie(cookie *Cookie) {
+	c := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(c)
+	c.SetKey(cookie.Name)
+	c.SetValue(cookie.Value)
+	c.SetPath(cookie.Path)
+	c.SetDomain(cookie.Domain)
+	if cookie.MaxAge > 0 {
+		c.SetMaxAge(cookie.MaxAge)
+	} else if cookie.MaxAge < 0 {
+		c.SetExpire(fasthttp.CookieExpireDelete)
+	} else if !cookie.Expires.IsZero() {
+		c.SetExpire(cookie.Expires)
+	}
+	c.SetSecure(cookie.Secure || cookie.Partitioned)
+	c.SetHTTPOnly(cookie.HTTPOnly)
+	switch cookie.SameSite {
+	case SameSiteLax:
+		c.SetSameSite(fasthttp.CookieSameSiteLaxMode)
+	case SameSiteStrict:
+		c.SetSameSite(fasthttp.CookieSameSiteStrictMode)
+	case SameSiteNone:
+		c.SetSameSite(fasthttp.CookieSameSiteNoneMode)
+	}
+	value := c.Cookie()
+	// fasthttp has no support for partitioned attribute
+	if cookie.Partitioned {
+		value = append(value, "; Partitioned"...)
+	}
+	ctx.RequestCtx.Response.Header.DelCookie(cookie.Name)
+	ctx.RequestCtx.Response.Header.SetBytesV(HeaderSetCookie, value)
+}
+
+// ClearCookie expires cookies with given names on the client
+func (ctx *Ctx) ClearCookie(names ...string) {
+	for _, name := range names {
+		ctx.SetCookie(&Cookie{
+			Name:   name,
+			Path:   "/",
+			MaxAge: -1,
+		})
+	}
+}
+
+// SetSignedCookie adds cookie signed with HMAC-SHA256 using first of
+// Config.CookieSigningKeys, value stays readable by client
+func (ctx *Ctx) SetSignedCookie(cookie *Cookie) error {
+	keys := ctx.cookieKeys(false)
+	if len(keys) == 0 {
+		return ErrNoCookieKeys
+	}
+	signed := *cookie
+	signed.Value = signCookieValue(keys[0], cookie.Name, cookie.Value)
+	ctx.SetCookie(&signed)
+	return nil
+}
+
+// SignedCookie returns value of a cookie set by SetSignedCookie,
+// any of Config.CookieSigningKeys is accepted so keys can be rotated
+func (ctx *Ctx) SignedCookie(name string) (string, error) {
+	keys := ctx.cookieKeys(false)
+	if len(keys) == 0 {
+		return "", ErrNoCookieKeys
+	}
+	raw := ctx.Cookie(name)
+	if raw == "" {
+		return "", ErrCookieNotFound
+	}
+	for _, key := range keys {
+		if value, ok := verifyCookieValue(key, name, raw); ok {
+			return value, nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
+
+// SetEncryptedCookie adds cookie encrypted with AES-GCM using first of
+// Config.CookieEncryptionKeys
+func (ctx *Ctx) SetEncryptedCookie(cookie *Cookie) error {
+	keys := ctx.cookieKeys(true)
+	if len(keys) == 0 {
+		return ErrNoCookieKeys
+	}
+	value, err := encryptCookieValue(keys[0], cookie.Name, cookie.Value)
+	if err != nil {
+		return err
+	}
+	encrypted := *cookie
+	encrypted.Value = value
+	ctx.SetCookie(&encrypted)
+	return nil
+}
+
+// EncryptedCookie returns value of a cookie set by SetEncryptedCookie,
+// any of Config.CookieEncryptionKeys is accepted so keys can be rotated
+func (ctx *Ctx) EncryptedCookie(name string) (string, error) {
+	keys := ctx.cookieKeys(true)
+	if len(keys) == 0 {
+		return "", ErrNoCookieKeys
+	}
+	raw := ctx.Cookie(name)
+	if raw == "" {
+		return "", ErrCookieNotFound
+	}
+	for _, key := range keys {
+		if value, err := decryptCookieValue(key, name, raw); err == nil {
+			return value, nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
+
+func (ctx *Ctx) cookieKeys(encryption bool) [][]byte {
+	if ctx.config == nil {
+		return nil
+	}
+	if encryption {
+		return ctx.config.CookieEncryptionKeys
+	}
+	return ctx.config.CookieSigningKeys
+}
+
+// value is encoded as base64(value).base64(mac), name is part
+// of the mac so values can not be moved between cookies
+func signCookieValue(key []byte, name, value string) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(value))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(cookieMAC(key, name, encoded))
+}
+
+func verifyCookieValue(key []byte, name, raw string) (string, bool) {
+	i := strings.LastIndexByte(raw, '.')
+	if i < 0 {
+		return "", false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(raw[i+1:])
+	if err != nil || !hmac.Equal(mac, cookieMAC(key, name, raw[:i])) {
+		return "", false
+	}
+	value, err := base64.RawURLEncoding.DecodeString(raw[:i])
+	if err != nil {
+		return "", false
+	}
+	return string(value), true
+}
+
+func cookieMAC(key []byte, name, value string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(name))
+	h.Write([]byte{'='})
+	h.Write([]byte(value))
+	return h.Sum(nil)
+}
+
+// value is encoded as base64(nonce|ciphertext), name is authenticated
+// as additional data
+func encryptCookieValue(key []byte, name, value string) (string, error) {
+	aead, err := cookieAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func decryptCookieValue(key []byte, name, raw string) (string, error) {
+	aead, err := cookieAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", ErrInvalidCookie
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	value, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func cookieAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
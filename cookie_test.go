@@ -0,0 +1,158 @@
+package slide
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CookieSuite struct {
+	suite.Suite
+	Slide *Slide
+}
+
+func (suite *CookieSuite) SetupTest() {
+	config := &Config{
+		CookieSigningKeys:    [][]byte{[]byte("new-signing-key"), []byte("old-signing-key")},
+		CookieEncryptionKeys: [][]byte{[]byte("0123456789abcdef"), []byte("fedcba9876543210")},
+	}
+	app := InitServer(config)
+	suite.Slide = app
+}
+
+func (suite *CookieSuite) TestSetCookie() {
+	path := "/hey"
+	suite.Slide.Get(path, func(ctx *Ctx) error {
+		ctx.SetCookie(&Cookie{
+			Name:        "session",
+			Value:       "slide",
+			Path:        "/",
+			MaxAge:      60,
+			HTTPOnly:    true,
+			SameSite:    SameSiteNone,
+			Partitioned: true,
+		})
+		return ctx.Send(http.StatusOK, ctx.Cookie("user"))
+	})
+	r, err := http.NewRequest(GET, "http://test"+path, nil)
+	if assert.Nil(suite.T(), err) {
+		r.AddCookie(&http.Cookie{Name: "user", Value: "madhuri"})
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			body, err := ioutil.ReadAll(res.Body)
+			if assert.Nil(suite.T(), err) {
+				assert.Equal(suite.T(), "madhuri", string(body))
+			}
+			header := res.Header.Get(HeaderSetCookie)
+			assert.True(suite.T(), strings.HasPrefix(header, "session=slide"))
+			for _, attr := range []string{"max-age=60", "path=/", "HttpOnly", "secure", "SameSite=None", "Partitioned"} {
+				assert.Contains(suite.T(), header, attr)
+			}
+		}
+	}
+}
+
+func (suite *CookieSuite) TestClearCookie() {
+	path := "/hey"
+	suite.Slide.Get(path, func(ctx *Ctx) error {
+		ctx.ClearCookie("session")
+		return ctx.SendStatusCode(http.StatusOK)
+	})
+	r, err := http.NewRequest(GET, "http://test"+path, nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			cookies := res.Cookies()
+			if assert.Len(suite.T(), cookies, 1) {
+				assert.Equal(suite.T(), "session", cookies[0].Name)
+				assert.Equal(suite.T(), "", cookies[0].Value)
+				assert.True(suite.T(), cookies[0].Expires.Before(time.Now()))
+			}
+		}
+	}
+}
+
+func (suite *CookieSuite) TestSignedCookie() {
+	config := suite.Slide.config
+	oldSigned := signCookieValue(config.CookieSigningKeys[1], "user", "slide")
+	tampered := signCookieValue([]byte("unknown"), "user", "slide")
+	tests := []struct {
+		value    string
+		expected string
+		err      error
+	}{
+		{signCookieValue(config.CookieSigningKeys[0], "user", "slide"), "slide", nil},
+		{oldSigned, "slide", nil},
+		{tampered, "", ErrInvalidCookie},
+		{signCookieValue(config.CookieSigningKeys[0], "other", "slide"), "", ErrInvalidCookie},
+	}
+	for _, test := range tests {
+		value, err := verifyCookie(suite, test.value, func(ctx *Ctx) (string, error) {
+			return ctx.SignedCookie("user")
+		})
+		assert.Equal(suite.T(), test.err, err)
+		assert.Equal(suite.T(), test.expected, value)
+	}
+}
+
+func (suite *CookieSuite) TestEncryptedCookie() {
+	config := suite.Slide.config
+	current, err := encryptCookieValue(config.CookieEncryptionKeys[0], "user", "slide")
+	assert.Nil(suite.T(), err)
+	old, err := encryptCookieValue(config.CookieEncryptionKeys[1], "user", "slide")
+	assert.Nil(suite.T(), err)
+	assert.NotContains(suite.T(), current, "slide")
+	for _, raw := range []string{current, old} {
+		value, err := verifyCookie(suite, raw, func(ctx *Ctx) (string, error) {
+			return ctx.EncryptedCookie("user")
+		})
+		assert.Nil(suite.T(), err)
+		assert.Equal(suite.T(), "slide", value)
+	}
+	_, err = verifyCookie(suite, current[:len(current)-2], func(ctx *Ctx) (string, error) {
+		return ctx.EncryptedCookie("user")
+	})
+	assert.Equal(suite.T(), ErrInvalidCookie, err)
+}
+
+// sends cookie user with raw value and returns what read returns
+func verifyCookie(suite *CookieSuite, raw string, read func(ctx *Ctx) (string, error)) (string, error) {
+	var value string
+	var readErr error
+	app := InitServer(suite.Slide.config)
+	app.Get("/hey", func(ctx *Ctx) error {
+		value, readErr = read(ctx)
+		return ctx.SendStatusCode(http.StatusOK)
+	})
+	r, err := http.NewRequest(GET, "http://test/hey", nil)
+	if assert.Nil(suite.T(), err) {
+		r.AddCookie(&http.Cookie{Name: "user", Value: raw})
+		_, err := testServer(r, app)
+		assert.Nil(suite.T(), err)
+	}
+	return value, readErr
+}
+
+func (suite *CookieSuite) TestSetSignedCookieWithoutKeys() {
+	suite.Slide.config = &Config{}
+	path := "/hey"
+	suite.Slide.Get(path, func(ctx *Ctx) error {
+		return ctx.SetSignedCookie(&Cookie{Name: "user", Value: "slide"})
+	})
+	r, err := http.NewRequest(GET, "http://test"+path, nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			assert.Equal(suite.T(), http.StatusInternalServerError, res.StatusCode)
+		}
+	}
+}
+
+func TestCookie(t *testing.T) {
+	suite.Run(t, new(CookieSuite))
+}
@@ -0,0 +1,72 @@
+package slide
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v4"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+// BodyDecoder decodes a request body into v
+type BodyDecoder func(body []byte, v interface{}) error
+
+var (
+	// ErrUnsupportedMediaType no decoder for Content-Type of the request body
+	ErrUnsupportedMediaType = NewError(http.StatusUnsupportedMediaType, "unsupported media type")
+)
+
+func defaultBodyDecoders() map[string]BodyDecoder {
+	return map[string]BodyDecoder{
+		ApplicationXML:            xml.Unmarshal,
+		TextXML:                   xml.Unmarshal,
+		ApplicationMsgPack:        msgpack.Unmarshal,
+		"application/x-msgpack":   msgpack.Unmarshal,
+		"application/vnd.msgpack": msgpack.Unmarshal,
+		ApplicationCBOR:           cbor.Unmarshal,
+		ApplicationYAML:           yaml.Unmarshal,
+		"application/x-yaml":      yaml.Unmarshal,
+		"text/yaml":               yaml.Unmarshal,
+		ApplicationProtobuf:       decodeProtobuf,
+		"application/x-protobuf":  decodeProtobuf,
+	}
+}
+
+// built in decoders with decoders of config
+func bodyDecoders(config *Config) map[string]BodyDecoder {
+	decoders := defaultBodyDecoders()
+	for mediaType, decoder := range config.BodyDecoders {
+		decoders[strings.ToLower(mediaType)] = decoder
+	}
+	return decoders
+}
+
+// returns decoder of media type, types with a structured syntax suffix
+// like application/problem+json fall back to the decoder of the suffix
+func (slide *Slide) bodyDecoder(mediaType string) (BodyDecoder, bool) {
+	if decoder, ok := slide.bodyDecoders[mediaType]; ok {
+		return decoder, true
+	}
+	if i := strings.LastIndex(mediaType, "+"); i != -1 {
+		decoder, ok := slide.bodyDecoders["application/"+mediaType[i+1:]]
+		return decoder, ok
+	}
+	return nil, false
+}
+
+func decodeProtobuf(body []byte, v interface{}) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%T is not a proto.Message", v)
+	}
+	return proto.Unmarshal(body, message)
+}
+
+// media type of Content-Type without parameters, lower cased
+func mediaType(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+}
@@ -0,0 +1,152 @@
+package slide
+
+import (
+	"errors"
+	"log"
+	"net/http"
+)
+
+// HTTPError error with the status code and message to respond with,
+// return it from handlers and middlewares
+//
+//	return slide.NewError(http.StatusNotFound, "user not found")
+//	return slide.ErrBadRequest.WithInternal(err)
+type HTTPError struct {
+	Code int `json:"-"`
+	// Message sent to the client, status text of Code when empty
+	Message string `json:"message"`
+	// Internal cause, never sent to the client, the default error
+	// handler and Problems log it to Config.ErrorLog
+	Internal error `json:"-"`
+	// Details sent to the client as JSON along with Message
+	Details interface{} `json:"details,omitempty"`
+}
+
+// NewError returns HTTPError of code, message defaults to status text of code
+func NewError(code int, message string) *HTTPError {
+	if message == "" {
+		message = http.StatusText(code)
+	}
+	return &HTTPError{Code: code, Message: message}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Internal == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Internal.Error()
+}
+
+// StatusCode status code to respond with
+func (e *HTTPError) StatusCode() int {
+	return e.Code
+}
+
+// Unwrap returns the internal cause
+func (e *HTTPError) Unwrap() error {
+	return e.Internal
+}
+
+// Is reports whether target has same code and message, so errors
+// with internal causes or details match the exported ones
+//
+//	errors.Is(err, slide.ErrNotFound)
+func (e *HTTPError) Is(target error) bool {
+	t, ok := target.(*HTTPError)
+	return ok && t.Code == e.Code && t.Message == e.Message
+}
+
+// WithInternal returns copy of error with the internal cause
+func (e *HTTPError) WithInternal(err error) *HTTPError {
+	c := *e
+	c.Internal = err
+	return &c
+}
+
+// WithDetails returns copy of error with details for the client
+func (e *HTTPError) WithDetails(details interface{}) *HTTPError {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// errors of common status codes with their status text as message
+var (
+	ErrBadRequest          = NewError(http.StatusBadRequest, "")
+	ErrUnauthorized        = NewError(http.StatusUnauthorized, "")
+	ErrForbidden           = NewError(http.StatusForbidden, "")
+	ErrNotFound            = NewError(http.StatusNotFound, "")
+	ErrMethodNotAllowed    = NewError(http.StatusMethodNotAllowed, "")
+	ErrNotAcceptable       = NewError(http.StatusNotAcceptable, "")
+	ErrConflict            = NewError(http.StatusConflict, "")
+	ErrGone                = NewError(http.StatusGone, "")
+	ErrUnprocessableEntity = NewError(http.StatusUnprocessableEntity, "")
+	ErrTooManyRequests     = NewError(http.StatusTooManyRequests, "")
+	ErrInternalServerError = NewError(http.StatusInternalServerError, "")
+	ErrNotImplemented      = NewError(http.StatusNotImplemented, "")
+	ErrBadGateway          = NewError(http.StatusBadGateway, "")
+	ErrServiceUnavailable  = NewError(http.StatusServiceUnavailable, "")
+	ErrGatewayTimeout      = NewError(http.StatusGatewayTimeout, "")
+	ErrRequestTimeout      = NewError(http.StatusRequestTimeout, "")
+	ErrPreconditionFailed  = NewError(http.StatusPreconditionFailed, "")
+)
+
+// responds with error when there is no error handler
+//
+// HTTPError responds with its code and message, as JSON when it has
+// details. ValidationError responds with its fields as JSON. Other
+// errors respond with their status code, 500 when they have none.
+// Internal causes are never sent and in production neither are messages
+// of 5xx errors, both are logged instead
+func (slide *Slide) defaultErrorHandler(ctx *Ctx, err error) {
+	slide.logHiddenError(ctx, err)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		if jsonErr := ctx.JSON(validationErr.StatusCode(), validationErr); jsonErr == nil {
+			return
+		}
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		if httpErr.Details != nil {
+			if jsonErr := ctx.JSON(httpErr.Code, httpErr); jsonErr == nil {
+				return
+			}
+		}
+		message := httpErr.Message
+		if slide.config.Production && httpErr.Code >= http.StatusInternalServerError {
+			message = http.StatusText(httpErr.Code)
+		}
+		_ = ctx.Send(httpErr.Code, message)
+		return
+	}
+	code := errorStatusCode(err)
+	message := err.Error()
+	if slide.config.Production && code >= http.StatusInternalServerError {
+		message = http.StatusText(code)
+	}
+	_ = ctx.Send(code, message)
+}
+
+// logs internal cause of err and errors whose message is hidden in production
+func (slide *Slide) logHiddenError(ctx *Ctx, err error) {
+	if slide == nil {
+		return
+	}
+	var httpErr *HTTPError
+	hasInternal := errors.As(err, &httpErr) && httpErr.Internal != nil
+	hidden := slide.config.Production && errorStatusCode(err) >= http.StatusInternalServerError
+	if !hasInternal && !hidden {
+		return
+	}
+	slide.logf("slide: %s %s: %v", ctx.RequestCtx.Method(), ctx.RequestCtx.Path(), err)
+}
+
+// logs to Config.ErrorLog, the standard logger when it is nil
+func (slide *Slide) logf(format string, args ...interface{}) {
+	if slide.config.ErrorLog != nil {
+		slide.config.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
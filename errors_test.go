@@ -0,0 +1,99 @@
+package slide
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ErrorsSuite struct {
+	suite.Suite
+}
+
+func (suite *ErrorsSuite) request(config *Config, err error) (int, string, string) {
+	app := InitServer(config)
+	app.Get("/", func(ctx *Ctx) error {
+		return err
+	})
+	r, reqErr := http.NewRequest(GET, "http://test/", nil)
+	if !assert.Nil(suite.T(), reqErr) {
+		return 0, "", ""
+	}
+	res, reqErr := testServer(r, app)
+	if !assert.Nil(suite.T(), reqErr) {
+		return 0, "", ""
+	}
+	body, reqErr := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), reqErr)
+	return res.StatusCode, res.Header.Get(ContentType), string(body)
+}
+
+func (suite *ErrorsSuite) TestNewError() {
+	err := NewError(http.StatusNotFound, "")
+	assert.Equal(suite.T(), "Not Found", err.Error())
+	assert.True(suite.T(), errors.Is(err, ErrNotFound))
+	wrapped := fmt.Errorf("find user: %w", ErrNotFound.WithInternal(errors.New("no rows")))
+	assert.True(suite.T(), errors.Is(wrapped, ErrNotFound))
+	assert.Equal(suite.T(), "Not Found: no rows", ErrNotFound.WithInternal(errors.New("no rows")).Error())
+	assert.Nil(suite.T(), ErrNotFound.Internal)
+}
+
+func (suite *ErrorsSuite) TestHTTPError() {
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+	err := NewError(http.StatusConflict, "user exists").WithInternal(errors.New("duplicate key"))
+	for _, production := range []bool{false, true} {
+		logs.Reset()
+		status, _, body := suite.request(&Config{Production: production, ErrorLog: logger}, err)
+		assert.Equal(suite.T(), http.StatusConflict, status)
+		assert.Equal(suite.T(), "user exists", body)
+		assert.Equal(suite.T(), "slide: GET /: user exists: duplicate key\n", logs.String())
+	}
+
+	// messages of 5xx errors are hidden in production
+	logs.Reset()
+	status, _, body := suite.request(&Config{Production: true, ErrorLog: logger}, NewError(http.StatusServiceUnavailable, "replica lag"))
+	assert.Equal(suite.T(), http.StatusServiceUnavailable, status)
+	assert.Equal(suite.T(), "Service Unavailable", body)
+	assert.Equal(suite.T(), "slide: GET /: replica lag\n", logs.String())
+
+	logs.Reset()
+	_, _, body = suite.request(&Config{ErrorLog: logger}, ErrNotFound)
+	assert.Equal(suite.T(), "Not Found", body)
+	assert.Empty(suite.T(), logs.String())
+}
+
+func (suite *ErrorsSuite) TestDetails() {
+	err := ErrBadRequest.WithDetails(map[string]string{"email": "taken"})
+	status, contentType, body := suite.request(&Config{}, err)
+	assert.Equal(suite.T(), http.StatusBadRequest, status)
+	assert.Equal(suite.T(), ApplicationJSON, contentType)
+	assert.Equal(suite.T(), `{"message":"Bad Request","details":{"email":"taken"}}`, body)
+}
+
+func (suite *ErrorsSuite) TestProductionHidesInternalErrors() {
+	err := errors.New("dial tcp 10.0.0.5:5432: connection refused")
+	status, _, body := suite.request(&Config{}, err)
+	assert.Equal(suite.T(), http.StatusInternalServerError, status)
+	assert.Equal(suite.T(), err.Error(), body)
+
+	status, _, body = suite.request(&Config{Production: true}, err)
+	assert.Equal(suite.T(), http.StatusInternalServerError, status)
+	assert.Equal(suite.T(), "Internal Server Error", body)
+
+	// client errors keep their message
+	status, _, body = suite.request(&Config{Production: true}, BindErrors{{Source: BindBody, Err: errors.New("unexpected EOF")}})
+	assert.Equal(suite.T(), http.StatusBadRequest, status)
+	assert.Equal(suite.T(), "bind body: unexpected EOF", body)
+}
+
+func TestErrors(t *testing.T) {
+	suite.Run(t, new(ErrorsSuite))
+}
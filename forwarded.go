@@ -0,0 +1,231 @@
+package slide
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ParseNetworks parses CIDRs or addresses like Config.TrustedProxies,
+// single addresses are turned into /32 or /128 networks
+func ParseNetworks(entries []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		cidr := entry
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", entry)
+			}
+			if ip.To4() != nil {
+				cidr = entry + "/32"
+			} else {
+				cidr = entry + "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q", entry)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// parses Config.TrustedProxies
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	networks, err := ParseNetworks(proxies)
+	if err != nil {
+		panic("slide: invalid trusted proxy, " + err.Error())
+	}
+	return networks
+}
+
+// canonical Config.ProxyHeader
+func parseProxyHeader(header string) string {
+	switch {
+	case header == "":
+		return HeaderXForwardedFor
+	case strings.EqualFold(header, HeaderXForwardedFor):
+		return HeaderXForwardedFor
+	case strings.EqualFold(header, HeaderForwarded):
+		return HeaderForwarded
+	case strings.EqualFold(header, HeaderXRealIP):
+		return HeaderXRealIP
+	}
+	panic(fmt.Sprintf("slide: invalid proxy header %q", header))
+}
+
+func (ctx *Ctx) proxyHeader() string {
+	if ctx.app == nil {
+		return HeaderXForwardedFor
+	}
+	return ctx.app.proxyHeader
+}
+
+func (ctx *Ctx) isTrustedProxy(ip net.IP) bool {
+	if ctx.app == nil {
+		return false
+	}
+	for _, network := range ctx.app.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IP returns address of the client, forwarded addresses are only
+// used when the request came through Config.TrustedProxies
+func (ctx *Ctx) IP() string {
+	return ctx.IPs()[0]
+}
+
+// IPs returns addresses the request came through, client first and the
+// connected address last. Forwarded addresses are read from
+// Config.ProxyHeader right to left while they belong to trusted proxies,
+// addresses before the first untrusted one can be spoofed and are left out
+func (ctx *Ctx) IPs() []string {
+	chain, _ := ctx.forwardedChain()
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// addresses from the connected one back to the client and the hop the
+// client address was taken from, counted from the right of the forwarded
+// headers so 0 is the hop added by the connected proxy
+func (ctx *Ctx) forwardedChain() ([]string, int) {
+	remote := ctx.RequestCtx.RemoteIP()
+	chain := []string{remote.String()}
+	hop := 0
+	if ctx.isTrustedProxy(remote) {
+		forwarded := ctx.forwardedFor()
+		for i := len(forwarded) - 1; i >= 0; i-- {
+			ip := net.ParseIP(forwarded[i])
+			if ip == nil {
+				break
+			}
+			chain = append(chain, ip.String())
+			hop = len(forwarded) - 1 - i
+			if !ctx.isTrustedProxy(ip) {
+				break
+			}
+		}
+	}
+	return chain, hop
+}
+
+// Scheme returns http or https as seen by the client, proto of Forwarded
+// or X-Forwarded-Proto is used for requests from trusted proxies, as set
+// by Config.ProxyHeader. It is read at the hop the client address of
+// ctx.IP comes from, values added before it can be spoofed
+func (ctx *Ctx) Scheme() string {
+	if ctx.isTrustedProxy(ctx.RequestCtx.RemoteIP()) {
+		proto := strings.ToLower(ctx.forwardedValue("proto", HeaderXForwardedProto))
+		if proto == "http" || proto == "https" {
+			return proto
+		}
+	}
+	if ctx.RequestCtx.IsTLS() {
+		return "https"
+	}
+	return "http"
+}
+
+// Host returns host requested by the client, host of Forwarded or
+// X-Forwarded-Host is used for requests from trusted proxies, read at
+// the same hop as Scheme
+func (ctx *Ctx) Host() string {
+	if ctx.isTrustedProxy(ctx.RequestCtx.RemoteIP()) {
+		if host := ctx.forwardedValue("host", HeaderXForwardedHost); host != "" {
+			return host
+		}
+	}
+	return string(ctx.RequestCtx.Host())
+}
+
+// forwarded client addresses of Config.ProxyHeader, client first
+func (ctx *Ctx) forwardedFor() []string {
+	header := &ctx.RequestCtx.Request.Header
+	switch ctx.proxyHeader() {
+	case HeaderForwarded:
+		var addresses []string
+		for _, element := range parseForwarded(string(header.Peek(HeaderForwarded))) {
+			addresses = append(addresses, forwardedNodeIP(element["for"]))
+		}
+		return addresses
+	case HeaderXRealIP:
+		if realIP := strings.TrimSpace(string(header.Peek(HeaderXRealIP))); realIP != "" {
+			return []string{realIP}
+		}
+		return nil
+	}
+	if xff := string(header.Peek(HeaderXForwardedFor)); xff != "" {
+		addresses := strings.Split(xff, ",")
+		for i := range addresses {
+			addresses[i] = strings.TrimSpace(addresses[i])
+		}
+		return addresses
+	}
+	return nil
+}
+
+// value of param in Forwarded or of header, depending on Config.ProxyHeader,
+// at the hop of the client address. Proxies setting a single value instead
+// of appending one are trusted with the leftmost value
+func (ctx *Ctx) forwardedValue(param, header string) string {
+	_, hop := ctx.forwardedChain()
+	if ctx.proxyHeader() == HeaderForwarded {
+		elements := parseForwarded(string(ctx.RequestCtx.Request.Header.Peek(HeaderForwarded)))
+		if len(elements) == 0 {
+			return ""
+		}
+		return elements[hopIndex(len(elements), hop)][param]
+	}
+	values := strings.Split(string(ctx.RequestCtx.Request.Header.Peek(header)), ",")
+	return strings.TrimSpace(values[hopIndex(len(values), hop)])
+}
+
+// index of hop counted from the right in a list of n values
+func hopIndex(n, hop int) int {
+	if i := n - 1 - hop; i > 0 {
+		return i
+	}
+	return 0
+}
+
+// parses Forwarded header into its elements
+// Reference https://tools.ietf.org/html/rfc7239#section-4
+func parseForwarded(header string) []map[string]string {
+	if header == "" {
+		return nil
+	}
+	var elements []map[string]string
+	for _, element := range strings.Split(header, ",") {
+		params := map[string]string{}
+		for _, pair := range strings.Split(element, ";") {
+			kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			params[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+		elements = append(elements, params)
+	}
+	return elements
+}
+
+// strips port and brackets from a Forwarded node, ex "[2001:db8::1]:4711"
+func forwardedNodeIP(node string) string {
+	if strings.HasPrefix(node, "[") {
+		if end := strings.Index(node, "]"); end > 0 {
+			return node[1:end]
+		}
+	}
+	if strings.Count(node, ":") == 1 {
+		return node[:strings.Index(node, ":")]
+	}
+	return node
+}
@@ -0,0 +1,131 @@
+package slide
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ForwardedSuite struct {
+	suite.Suite
+}
+
+// requests of testServer come from 0.0.0.0
+func (suite *ForwardedSuite) request(config *Config, headers map[string]string) string {
+	app := InitServer(config)
+	app.Get("/ip", func(ctx *Ctx) error {
+		return ctx.Send(http.StatusOK, fmt.Sprintf("%s|%s|%s|%s", ctx.IP(), strings.Join(ctx.IPs(), ","), ctx.Scheme(), ctx.Host()))
+	})
+	r, err := http.NewRequest(GET, "http://test/ip", nil)
+	if !assert.Nil(suite.T(), err) {
+		return ""
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return ""
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return string(body)
+}
+
+func (suite *ForwardedSuite) TestUntrustedProxy() {
+	body := suite.request(&Config{}, map[string]string{
+		HeaderXForwardedFor:   "1.1.1.1",
+		HeaderXForwardedProto: "https",
+		HeaderXForwardedHost:  "example.com",
+	})
+	assert.Equal(suite.T(), "0.0.0.0|0.0.0.0|http|test", body)
+}
+
+func (suite *ForwardedSuite) TestXForwardedFor() {
+	body := suite.request(&Config{TrustedProxies: []string{"0.0.0.0", "10.0.0.0/8"}}, map[string]string{
+		HeaderXForwardedFor:   "6.6.6.6, 1.1.1.1, 10.0.0.2",
+		HeaderXForwardedProto: "https",
+		HeaderXForwardedHost:  "example.com",
+	})
+	assert.Equal(suite.T(), "1.1.1.1|1.1.1.1,10.0.0.2,0.0.0.0|https|example.com", body)
+}
+
+func (suite *ForwardedSuite) TestXRealIP() {
+	body := suite.request(&Config{TrustedProxies: []string{"0.0.0.0"}, ProxyHeader: HeaderXRealIP}, map[string]string{
+		HeaderXRealIP: "1.1.1.1",
+	})
+	assert.Equal(suite.T(), "1.1.1.1|1.1.1.1,0.0.0.0|http|test", body)
+}
+
+func (suite *ForwardedSuite) TestForwarded() {
+	body := suite.request(&Config{TrustedProxies: []string{"0.0.0.0", "2001:db8::/32"}, ProxyHeader: HeaderForwarded}, map[string]string{
+		HeaderForwarded:     `for=1.1.1.1:4711;proto=https;host=example.com, for="[2001:db8::17]:80"`,
+		HeaderXForwardedFor: "6.6.6.6",
+	})
+	assert.Equal(suite.T(), "1.1.1.1|1.1.1.1,2001:db8::17,0.0.0.0|https|example.com", body)
+}
+
+func (suite *ForwardedSuite) TestSpoofedValues() {
+	// client sent the leftmost values, the trusted proxy appended its own
+	body := suite.request(&Config{TrustedProxies: []string{"0.0.0.0"}}, map[string]string{
+		HeaderXForwardedFor:   "6.6.6.6, 1.1.1.1",
+		HeaderXForwardedProto: "http, https",
+		HeaderXForwardedHost:  "evil.com, example.com",
+	})
+	assert.Equal(suite.T(), "1.1.1.1|1.1.1.1,0.0.0.0|https|example.com", body)
+
+	body = suite.request(&Config{TrustedProxies: []string{"0.0.0.0"}, ProxyHeader: HeaderForwarded}, map[string]string{
+		HeaderForwarded: `for=6.6.6.6;proto=http;host=evil.com, for=1.1.1.1;proto=https;host=example.com`,
+	})
+	assert.Equal(suite.T(), "1.1.1.1|1.1.1.1,0.0.0.0|https|example.com", body)
+}
+
+func (suite *ForwardedSuite) TestOnlyProxyHeader() {
+	// the proxy only appends X-Forwarded-For, other headers come from the client
+	body := suite.request(&Config{TrustedProxies: []string{"0.0.0.0"}}, map[string]string{
+		HeaderForwarded:     "for=10.1.2.3;proto=https;host=evil.com",
+		HeaderXRealIP:       "10.1.2.4",
+		HeaderXForwardedFor: "1.1.1.1",
+	})
+	assert.Equal(suite.T(), "1.1.1.1|1.1.1.1,0.0.0.0|http|test", body)
+
+	// no fallback when the proxy header is missing
+	body = suite.request(&Config{TrustedProxies: []string{"0.0.0.0"}}, map[string]string{
+		HeaderForwarded: "for=10.1.2.3;proto=https;host=evil.com",
+	})
+	assert.Equal(suite.T(), "0.0.0.0|0.0.0.0|http|test", body)
+
+	body = suite.request(&Config{TrustedProxies: []string{"0.0.0.0"}, ProxyHeader: HeaderForwarded}, map[string]string{
+		HeaderXForwardedFor:   "10.1.2.3",
+		HeaderXForwardedProto: "https",
+		HeaderXForwardedHost:  "evil.com",
+	})
+	assert.Equal(suite.T(), "0.0.0.0|0.0.0.0|http|test", body)
+
+	assert.Panics(suite.T(), func() {
+		InitServer(&Config{ProxyHeader: "X-Client-IP"})
+	})
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	assert.Len(t, parseTrustedProxies([]string{"10.0.0.1", "::1", "192.168.0.0/16"}), 3)
+	assert.Panics(t, func() {
+		parseTrustedProxies([]string{"10.0.0.300"})
+	})
+	networks, err := ParseNetworks([]string{"10.0.0.1", "2001:db8::/32"})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "10.0.0.1/32", networks[0].String())
+		assert.Equal(t, "2001:db8::/32", networks[1].String())
+	}
+	_, err = ParseNetworks([]string{"10.0.0.0/33"})
+	assert.NotNil(t, err)
+}
+
+func TestForwarded(t *testing.T) {
+	suite.Run(t, new(ForwardedSuite))
+}
@@ -3,8 +3,16 @@ module github.com/go-slide/slide
 go 1.13
 
 require (
+	github.com/andybalholm/brotli v1.0.2
+	github.com/fxamacker/cbor/v2 v2.2.0
 	github.com/go-playground/assert/v2 v2.0.1
+	github.com/go-playground/locales v0.13.0
+	github.com/go-playground/universal-translator v0.17.0
 	github.com/go-playground/validator/v10 v10.3.0
+	github.com/klauspost/compress v1.13.4
 	github.com/stretchr/testify v1.6.1
-	github.com/valyala/fasthttp v1.14.0
+	github.com/valyala/fasthttp v1.32.0
+	github.com/vmihailenco/msgpack/v4 v4.3.12
+	google.golang.org/protobuf v1.25.0
+	gopkg.in/yaml.v2 v2.3.0
 )
@@ -0,0 +1,203 @@
+package slide
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// JSONCodec encodes JSON responses and decodes JSON bodies of ctx.Bind,
+// set Config.JSONCodec to use a faster library, encoding/json otherwise.
+// Encoders and decoders of most libraries already satisfy it
+//
+//	type codec struct{}
+//
+//	func (codec) NewEncoder(w io.Writer) slide.JSONEncoder { return gojson.NewEncoder(w) }
+//	func (codec) NewDecoder(r io.Reader) slide.JSONDecoder { return gojson.NewDecoder(r) }
+type JSONCodec interface {
+	NewEncoder(w io.Writer) JSONEncoder
+	NewDecoder(r io.Reader) JSONDecoder
+}
+
+// JSONEncoder writes JSON values to a stream, like json.Encoder
+type JSONEncoder interface {
+	Encode(v interface{}) error
+	SetEscapeHTML(on bool)
+	SetIndent(prefix, indent string)
+}
+
+// JSONDecoder reads JSON values from a stream, like json.Decoder
+type JSONDecoder interface {
+	Decode(v interface{}) error
+	DisallowUnknownFields()
+	UseNumber()
+}
+
+// encoding/json
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) NewEncoder(w io.Writer) JSONEncoder {
+	return json.NewEncoder(w)
+}
+
+func (stdJSONCodec) NewDecoder(r io.Reader) JSONDecoder {
+	return json.NewDecoder(r)
+}
+
+// BindOption option of ctx.Bind
+type BindOption func(*bindOptions)
+
+type bindOptions struct {
+	disallowUnknownFields bool
+	useNumber             bool
+}
+
+// DisallowUnknownFields fails binding of JSON bodies having fields
+// which are not in the input
+func DisallowUnknownFields() BindOption {
+	return func(o *bindOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// UseNumber decodes numbers of JSON bodies into interface{} values as
+// json.Number instead of float64
+func UseNumber() BindOption {
+	return func(o *bindOptions) {
+		o.useNumber = true
+	}
+}
+
+func (ctx *Ctx) jsonCodec() JSONCodec {
+	if ctx.config != nil && ctx.config.JSONCodec != nil {
+		return ctx.config.JSONCodec
+	}
+	return stdJSONCodec{}
+}
+
+func (ctx *Ctx) newJSONEncoder(w io.Writer) JSONEncoder {
+	encoder := ctx.jsonCodec().NewEncoder(w)
+	encoder.SetEscapeHTML(ctx.config == nil || !ctx.config.DisableJSONHTMLEscape)
+	return encoder
+}
+
+// encodes payload without the newline added by encoders
+func (ctx *Ctx) marshalJSON(payload interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := ctx.newJSONEncoder(&buf)
+	if indent != "" {
+		encoder.SetIndent("", indent)
+	}
+	if err := encoder.Encode(payload); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (ctx *Ctx) decodeJSON(body []byte, v interface{}, options bindOptions) error {
+	decoder := ctx.jsonCodec().NewDecoder(bytes.NewReader(body))
+	if options.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if options.useNumber {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	// bodies are a single value, only whitespace can follow it
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		return errors.New("json: unexpected data after top-level value")
+	}
+	return nil
+}
+
+// media type decoded by JSONCodec, including types with the +json suffix
+func isJSON(mediaType string) bool {
+	return mediaType == ApplicationJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// JSONStream sends items, a slice, array or channel, as a JSON array
+// written to the connection element by element without buffering the
+// whole response. Channels are read until closed
+//
+//	rows := make(chan Row)
+//	go func() {
+//		defer close(rows)
+//		for ... {
+//			rows <- row
+//		}
+//	}()
+//	return ctx.JSONStream(http.StatusOK, rows)
+//
+// The status is sent before the items are encoded, so encoding errors
+// and disconnects end the response early with an incomplete array
+func (ctx *Ctx) JSONStream(statusCode int, items interface{}) error {
+	v := reflect.ValueOf(items)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+	case reflect.Chan:
+		if v.Type().ChanDir()&reflect.RecvDir == 0 {
+			return fmt.Errorf("json stream: send only channel %s", v.Type())
+		}
+	default:
+		return fmt.Errorf("json stream: unsupported type %T", items)
+	}
+	ctx.RequestCtx.Response.Header.Set(ContentType, ApplicationJSON)
+	ctx.RequestCtx.SetStatusCode(statusCode)
+	ctx.RequestCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		encoder := ctx.newJSONEncoder(w)
+		failed := false
+		count := 0
+		write := func(item reflect.Value) {
+			if failed {
+				return
+			}
+			separator := ",\n"
+			if count == 0 {
+				separator = "[\n"
+			}
+			count++
+			if _, err := w.WriteString(separator); err != nil {
+				failed = true
+				return
+			}
+			if err := encoder.Encode(item.Interface()); err != nil {
+				failed = true
+				return
+			}
+			// sends buffered items so clients can process them early
+			if w.Buffered() >= w.Size()/2 {
+				failed = w.Flush() != nil
+			}
+		}
+		if v.Kind() == reflect.Chan {
+			// drained even after failures so senders are not blocked
+			for {
+				item, ok := v.Recv()
+				if !ok {
+					break
+				}
+				write(item)
+			}
+		} else {
+			for i := 0; i < v.Len(); i++ {
+				write(v.Index(i))
+			}
+		}
+		if failed {
+			return
+		}
+		if count == 0 {
+			_, _ = w.WriteString("[")
+		}
+		_, _ = w.WriteString("]")
+		_ = w.Flush()
+	})
+	return nil
+}
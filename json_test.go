@@ -0,0 +1,141 @@
+package slide
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type JSONSuite struct {
+	suite.Suite
+}
+
+// counts encoders and decoders created by the app
+type countingJSONCodec struct {
+	encoders, decoders int
+}
+
+func (c *countingJSONCodec) NewEncoder(w io.Writer) JSONEncoder {
+	c.encoders++
+	return json.NewEncoder(w)
+}
+
+func (c *countingJSONCodec) NewDecoder(r io.Reader) JSONDecoder {
+	c.decoders++
+	return json.NewDecoder(r)
+}
+
+type jsonInput struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+func (suite *JSONSuite) request(config *Config, body string, h handler) (int, string) {
+	app := InitServer(config)
+	app.Post("/", h)
+	r, err := http.NewRequest(POST, "http://test/", strings.NewReader(body))
+	if !assert.Nil(suite.T(), err) {
+		return 0, ""
+	}
+	r.Header.Set(ContentType, ApplicationJSON)
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return 0, ""
+	}
+	response, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res.StatusCode, string(response)
+}
+
+func (suite *JSONSuite) TestCodec() {
+	codec := &countingJSONCodec{}
+	_, body := suite.request(&Config{JSONCodec: codec}, `{"name":"slide"}`, func(ctx *Ctx) error {
+		input := &jsonInput{}
+		if err := ctx.Bind(input); err != nil {
+			return err
+		}
+		return ctx.JSON(http.StatusOK, input)
+	})
+	assert.Equal(suite.T(), `{"name":"slide","value":null}`, body)
+	assert.Equal(suite.T(), 1, codec.encoders)
+	assert.Equal(suite.T(), 1, codec.decoders)
+}
+
+func (suite *JSONSuite) TestHTMLEscape() {
+	h := func(ctx *Ctx) error {
+		return ctx.JSON(http.StatusOK, "<b>&</b>")
+	}
+	_, body := suite.request(&Config{}, "", h)
+	assert.Equal(suite.T(), `"\u003cb\u003e\u0026\u003c/b\u003e"`, body)
+	_, body = suite.request(&Config{DisableJSONHTMLEscape: true}, "", h)
+	assert.Equal(suite.T(), `"<b>&</b>"`, body)
+}
+
+func (suite *JSONSuite) TestBindOptions() {
+	var bindErr error
+	input := &jsonInput{}
+	suite.request(&Config{}, `{"name":"slide","value":1.5e3,"extra":true}`, func(ctx *Ctx) error {
+		bindErr = ctx.Bind(input, UseNumber())
+		return bindErr
+	})
+	if assert.Nil(suite.T(), bindErr) {
+		assert.Equal(suite.T(), json.Number("1.5e3"), input.Value)
+	}
+
+	status, _ := suite.request(&Config{}, `{"name":"slide","extra":true}`, func(ctx *Ctx) error {
+		bindErr = ctx.Bind(&jsonInput{}, DisallowUnknownFields())
+		return bindErr
+	})
+	var bindErrs BindErrors
+	if assert.True(suite.T(), errors.As(bindErr, &bindErrs)) {
+		assert.Equal(suite.T(), BindBody, bindErrs[0].Source)
+	}
+	assert.Equal(suite.T(), http.StatusBadRequest, status)
+}
+
+func (suite *JSONSuite) TestStream() {
+	_, body := suite.request(&Config{}, "", func(ctx *Ctx) error {
+		return ctx.JSONStream(http.StatusOK, []jsonInput{{Name: "a"}, {Name: "b"}})
+	})
+	var items []jsonInput
+	if assert.Nil(suite.T(), json.Unmarshal([]byte(body), &items), body) {
+		assert.Equal(suite.T(), []jsonInput{{Name: "a"}, {Name: "b"}}, items)
+	}
+
+	_, body = suite.request(&Config{}, "", func(ctx *Ctx) error {
+		numbers := make(chan int)
+		go func() {
+			defer close(numbers)
+			for i := 0; i < 1000; i++ {
+				numbers <- i
+			}
+		}()
+		return ctx.JSONStream(http.StatusOK, numbers)
+	})
+	var numbers []int
+	if assert.Nil(suite.T(), json.Unmarshal([]byte(body), &numbers)) {
+		assert.Len(suite.T(), numbers, 1000)
+		assert.Equal(suite.T(), 999, numbers[999])
+	}
+
+	_, body = suite.request(&Config{}, "", func(ctx *Ctx) error {
+		return ctx.JSONStream(http.StatusOK, []int{})
+	})
+	assert.Equal(suite.T(), "[]", body)
+
+	status, _ := suite.request(&Config{}, "", func(ctx *Ctx) error {
+		return ctx.JSONStream(http.StatusOK, map[string]int{})
+	})
+	assert.Equal(suite.T(), http.StatusInternalServerError, status)
+}
+
+func TestJSON(t *testing.T) {
+	suite.Run(t, new(JSONSuite))
+}
@@ -1,6 +1,9 @@
 package slide
 
-import "strings"
+import (
+	"sort"
+	"strings"
+)
 
 func appLevelMiddleware(ctx *Ctx, slide *Slide) {
 	if len(slide.middleware) > 0 {
@@ -30,33 +33,56 @@ func appLevelMiddleware(ctx *Ctx, slide *Slide) {
 }
 
 func groupLevelMiddleware(ctx *Ctx, slide *Slide, routers []router) {
-	path := string(ctx.RequestCtx.Path())
-	// check if path is available in Group middleware
-	if len(slide.groupMiddlewareMap) == 0 {
+	middlewares := matchGroupMiddleware(slide, string(ctx.RequestCtx.Path()))
+	if len(middlewares) == 0 {
 		handleRouter(ctx, slide, routers)
 		return
 	}
-	for groupPath, groupMiddlewares := range slide.groupMiddlewareMap {
-		// replace this with wild card
-		if strings.Contains(path, groupPath) && len(groupMiddlewares) > 0 {
-			var next func() error
-			next = func() error {
-				ctx.groupMiddlewareIndex = ctx.groupMiddlewareIndex + 1
-				if ctx.groupMiddlewareIndex != len(groupMiddlewares) {
-					handler := groupMiddlewares[ctx.groupMiddlewareIndex]
-					if err := handler(ctx); err != nil {
-						handlerRouterError(err, ctx, slide)
-					}
-				}
-				return nil
-			}
-			ctx.groupMiddlewareIndex = 0
-			ctx.Next = next
-			handler := groupMiddlewares[ctx.groupMiddlewareIndex]
+	// outer group middlewares run first and the last one hands over to the route
+	ctx.groupMiddlewareIndex = 0
+	var next func() error
+	next = func() error {
+		ctx.groupMiddlewareIndex = ctx.groupMiddlewareIndex + 1
+		if ctx.groupMiddlewareIndex != len(middlewares) {
+			handler := middlewares[ctx.groupMiddlewareIndex]
 			if err := handler(ctx); err != nil {
 				handlerRouterError(err, ctx, slide)
 			}
+		} else {
+			handleRouter(ctx, slide, routers)
+		}
+		return nil
+	}
+	ctx.Next = next
+	handler := middlewares[ctx.groupMiddlewareIndex]
+	if err := handler(ctx); err != nil {
+		handlerRouterError(err, ctx, slide)
+	}
+}
+
+// returns middlewares of all groups containing path, outer groups first
+func matchGroupMiddleware(slide *Slide, path string) []handler {
+	var groupPaths []string
+	for groupPath, groupMiddlewares := range slide.groupMiddlewareMap {
+		if len(groupMiddlewares) > 0 && isGroupPath(groupPath, path) {
+			groupPaths = append(groupPaths, groupPath)
 		}
 	}
-	handleRouter(ctx, slide, routers)
+	sort.Strings(groupPaths)
+	var middlewares []handler
+	for _, groupPath := range groupPaths {
+		middlewares = append(middlewares, slide.groupMiddlewareMap[groupPath]...)
+	}
+	return middlewares
+}
+
+// /auth contains /auth and /auth/login but not /authors
+func isGroupPath(groupPath, path string) bool {
+	if groupPath == "" || groupPath == path {
+		return true
+	}
+	if strings.HasSuffix(groupPath, "/") {
+		return strings.HasPrefix(path, groupPath)
+	}
+	return strings.HasPrefix(path, groupPath+"/")
 }
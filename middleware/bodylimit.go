@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-slide/slide"
+)
+
+var byteUnits = map[string]float64{
+	"":   1,
+	"B":  1,
+	"K":  1 << 10,
+	"KB": 1 << 10,
+	"M":  1 << 20,
+	"MB": 1 << 20,
+	"G":  1 << 30,
+	"GB": 1 << 30,
+	"T":  1 << 40,
+	"TB": 1 << 40,
+}
+
+// BodyLimit limits size of request bodies, limit is a size like "512KB",
+// "2MB" or "1.5GB" with units of 1024 bytes, "0" removes the limit.
+// Requests with larger bodies get 413 before the route handler runs, and
+// from ctx.Body, ctx.Bind and ctx.UploadFile in middlewares before it
+//
+// Use it with app.Use, group.Use or as route middleware, the limit set last
+// wins so a route can override the limit of its app or group
+//
+//	app.Use(middleware.BodyLimit("2MB"))
+//	app.Post("/upload", upload, middleware.BodyLimit("100MB"))
+//
+// Listen streams bodies larger than Config.MaxRequestBodySize (4MB by
+// default) and reads them only up to the limit, so limits can be above it
+func BodyLimit(limit string) func(ctx *slide.Ctx) error {
+	size, err := parseByteSize(limit)
+	if err != nil {
+		panic(err)
+	}
+	return func(ctx *slide.Ctx) error {
+		ctx.SetBodyLimit(size)
+		return ctx.Next()
+	}
+}
+
+func parseByteSize(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+	unit, ok := byteUnits[strings.TrimSpace(s[i:])]
+	if !ok {
+		return 0, fmt.Errorf("body limit: invalid size %q", size)
+	}
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("body limit: invalid size %q", size)
+	}
+	return int64(n * unit), nil
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		size  string
+		bytes int64
+		valid bool
+	}{
+		{"0", 0, true},
+		{"512", 512, true},
+		{"512B", 512, true},
+		{"1K", 1 << 10, true},
+		{"512kb", 512 << 10, true},
+		{"2MB", 2 << 20, true},
+		{" 2 MB ", 2 << 20, true},
+		{"1.5GB", 3 << 29, true},
+		{"0.5M", 1 << 19, true},
+		{"1TB", 1 << 40, true},
+		{"", 0, false},
+		{"MB", 0, false},
+		{"-1MB", 0, false},
+		{"1.2.3KB", 0, false},
+		{"10XB", 0, false},
+		{"2MiB", 0, false},
+	}
+	for _, test := range tests {
+		bytes, err := parseByteSize(test.size)
+		if test.valid {
+			assert.Nil(t, err, test.size)
+			assert.Equal(t, test.bytes, bytes, test.size)
+		} else {
+			assert.NotNil(t, err, test.size)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package middleware
+
+import (
+	"container/list"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-slide/slide"
+	"github.com/valyala/fasthttp"
+)
+
+// CachedResponse response stored by Cache middleware
+type CachedResponse struct {
+	StatusCode int
+	Headers    [][2]string
+	Body       []byte
+	Stored     time.Time
+	// FreshUntil response is served without running the handler until then
+	FreshUntil time.Time
+	// StaleUntil response is served while being revalidated until then
+	StaleUntil time.Time
+}
+
+// CacheStore storage of Cache middleware
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, response *CachedResponse)
+	Delete(key string)
+}
+
+// CacheConfig configuration for Cache middleware
+type CacheConfig struct {
+	// Store defaults to an in-memory LRU store of 1024 responses
+	Store CacheStore
+	// TTL freshness of responses without max-age, defaults to 1 minute
+	TTL time.Duration
+	// StaleWhileRevalidate how long stale responses are served while refreshed
+	// in background, responses can override it with stale-while-revalidate
+	StaleWhileRevalidate time.Duration
+	// KeyHeaders request headers which are part of the cache key, ex Accept-Language.
+	// Responses with Vary are only stored when KeyHeaders has every header they
+	// vary on, add Accept-Encoding when Compress runs after Cache
+	KeyHeaders []string
+}
+
+var (
+	// DefaultCacheConfig default config for cache
+	DefaultCacheConfig = CacheConfig{
+		TTL: time.Minute,
+	}
+)
+
+// cache status header for debugging
+const headerXCache = "X-Cache"
+
+// Cache middleware caching full responses of GET and HEAD requests, keyed by
+// method, path, query and KeyHeaders. Cache-Control of requests (no-store,
+// no-cache) and responses (no-store, private, no-cache, max-age, s-maxage,
+// stale-while-revalidate) is respected, concurrent misses of a key wait for
+// a single handler run
+//
+// Stale responses are refreshed by running the request through the app
+// again without Cookie, Authorization and conditional headers. When
+// KeyHeaders has Cookie or Authorization, stale responses are refreshed
+// by the next request instead
+func Cache(config CacheConfig) func(ctx *slide.Ctx) error {
+	if config.Store == nil {
+		config.Store = NewLRUCacheStore(1024)
+	}
+	if config.TTL == 0 {
+		config.TTL = DefaultCacheConfig.TTL
+	}
+	keyed := map[string]bool{}
+	for _, h := range config.KeyHeaders {
+		keyed[strings.ToLower(h)] = true
+	}
+	// refreshed responses have to be the same for every client of the key
+	revalidate := !keyed["authorization"] && !keyed["cookie"]
+	flights := &cacheFlights{calls: map[string]*cacheCall{}}
+	revalidating := &sync.Map{}
+	return func(ctx *slide.Ctx) error {
+		if !(ctx.RequestCtx.IsGet() || ctx.RequestCtx.IsHead()) {
+			return ctx.Next()
+		}
+		requestCC := parseCacheControl(string(ctx.RequestCtx.Request.Header.Peek(slide.HeaderCacheControl)))
+		if _, ok := requestCC["no-store"]; ok {
+			return ctx.Next()
+		}
+		key := cacheKey(ctx, config.KeyHeaders)
+		_, noCache := requestCC["no-cache"]
+		if !noCache && requestCC["max-age"] != "0" {
+			if cached, ok := config.Store.Get(key); ok {
+				now := time.Now()
+				if now.Before(cached.FreshUntil) {
+					writeCachedResponse(ctx, cached, "HIT")
+					return nil
+				}
+				if revalidate && now.Before(cached.StaleUntil) {
+					writeCachedResponse(ctx, cached, "STALE")
+					revalidateInBackground(ctx, key, revalidating)
+					return nil
+				}
+			}
+		}
+		call, leader := flights.join(key)
+		if !leader {
+			call.wg.Wait()
+			if call.response != nil {
+				writeCachedResponse(ctx, call.response, "HIT")
+				return nil
+			}
+			// response of leader was not cacheable
+			ctx.RequestCtx.Response.Header.Set(headerXCache, "MISS")
+			return ctx.Next()
+		}
+		defer flights.done(key, call)
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+		if cached := captureResponse(ctx, &config, keyed); cached != nil {
+			config.Store.Set(key, cached)
+			call.response = cached
+		}
+		ctx.RequestCtx.Response.Header.Set(headerXCache, "MISS")
+		return nil
+	}
+}
+
+func cacheKey(ctx *slide.Ctx, headers []string) string {
+	var b strings.Builder
+	b.Write(ctx.RequestCtx.Method())
+	b.WriteByte(' ')
+	b.Write(ctx.RequestCtx.Path())
+	b.WriteByte('?')
+	b.Write(ctx.RequestCtx.URI().QueryString())
+	for _, h := range headers {
+		b.WriteByte('\n')
+		b.WriteString(h)
+		b.WriteByte(':')
+		b.Write(ctx.RequestCtx.Request.Header.Peek(h))
+	}
+	return b.String()
+}
+
+// returns directives of Cache-Control with their values, "" for directives without value
+func parseCacheControl(header string) map[string]string {
+	directives := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		name := strings.ToLower(strings.TrimSpace(kv[0]))
+		if len(kv) == 2 {
+			directives[name] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		} else {
+			directives[name] = ""
+		}
+	}
+	return directives
+}
+
+func directiveSeconds(directives map[string]string, name string) (time.Duration, bool) {
+	v, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(v)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// returns nil if response must not be cached, keyed has the lower cased
+// KeyHeaders
+func captureResponse(ctx *slide.Ctx, config *CacheConfig, keyed map[string]bool) *CachedResponse {
+	response := &ctx.RequestCtx.Response
+	if response.StatusCode() != http.StatusOK || response.IsBodyStream() ||
+		len(response.Header.Peek(slide.HeaderSetCookie)) > 0 {
+		return nil
+	}
+	// the key has to cover every header the response varies on, "*" is never stored
+	for _, v := range strings.Split(string(response.Header.Peek(slide.HeaderVary)), ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" && !keyed[v] {
+			return nil
+		}
+	}
+	cc := parseCacheControl(string(response.Header.Peek(slide.HeaderCacheControl)))
+	for _, d := range []string{"no-store", "private", "no-cache"} {
+		if _, ok := cc[d]; ok {
+			return nil
+		}
+	}
+	// responses to authorized requests are shared only when allowed
+	// Reference https://tools.ietf.org/html/rfc7234#section-3.2
+	if len(ctx.RequestCtx.Request.Header.Peek(slide.HeaderAuthorization)) > 0 && !keyed["authorization"] {
+		_, public := cc["public"]
+		_, sMaxAge := cc["s-maxage"]
+		_, mustRevalidate := cc["must-revalidate"]
+		if !public && !sMaxAge && !mustRevalidate {
+			return nil
+		}
+	}
+	ttl := config.TTL
+	if maxAge, ok := directiveSeconds(cc, "s-maxage"); ok {
+		ttl = maxAge
+	} else if maxAge, ok := directiveSeconds(cc, "max-age"); ok {
+		ttl = maxAge
+	}
+	stale := config.StaleWhileRevalidate
+	if swr, ok := directiveSeconds(cc, "stale-while-revalidate"); ok {
+		stale = swr
+	}
+	if ttl <= 0 && stale <= 0 {
+		return nil
+	}
+	now := time.Now()
+	cached := &CachedResponse{
+		StatusCode: response.StatusCode(),
+		Body:       append([]byte(nil), response.Body()...),
+		Stored:     now,
+		FreshUntil: now.Add(ttl),
+		StaleUntil: now.Add(ttl + stale),
+	}
+	response.Header.VisitAll(func(key, value []byte) {
+		switch string(key) {
+		case slide.HeaderContentLength, slide.HeaderSetCookie, headerXCache, "Date":
+			return
+		}
+		cached.Headers = append(cached.Headers, [2]string{string(key), string(value)})
+	})
+	return cached
+}
+
+func writeCachedResponse(ctx *slide.Ctx, cached *CachedResponse, status string) {
+	response := &ctx.RequestCtx.Response
+	for _, h := range cached.Headers {
+		response.Header.Set(h[0], h[1])
+	}
+	response.Header.Set(slide.HeaderAge, strconv.Itoa(int(time.Since(cached.Stored)/time.Second)))
+	response.Header.Set(headerXCache, status)
+	response.SetStatusCode(cached.StatusCode)
+	response.SetBody(cached.Body)
+}
+
+// runs the request again through the app with no-cache, so the
+// cache middleware of that run stores a fresh response. The refresh is
+// shared by every client of the key, so credentials and conditions of
+// the client are left out
+func revalidateInBackground(ctx *slide.Ctx, key string, revalidating *sync.Map) {
+	if _, running := revalidating.LoadOrStore(key, true); running {
+		return
+	}
+	req := &fasthttp.Request{}
+	ctx.RequestCtx.Request.CopyTo(req)
+	req.Header.DelAllCookies()
+	for _, h := range []string{slide.HeaderAuthorization, slide.HeaderIfMatch, slide.HeaderIfNoneMatch,
+		slide.HeaderIfModifiedSince, slide.HeaderIfUnmodifiedSince} {
+		req.Header.Del(h)
+	}
+	req.Header.Set(slide.HeaderCacheControl, "no-cache")
+	remoteAddr := ctx.RequestCtx.RemoteAddr()
+	handler := ctx.App().Handler()
+	go func() {
+		defer revalidating.Delete(key)
+		var rc fasthttp.RequestCtx
+		rc.Init(req, remoteAddr, nil)
+		handler(&rc)
+	}()
+}
+
+// cacheFlights coalesces concurrent misses of a key
+type cacheFlights struct {
+	mu    sync.Mutex
+	calls map[string]*cacheCall
+}
+
+type cacheCall struct {
+	wg       sync.WaitGroup
+	response *CachedResponse
+}
+
+// returns call of key and whether caller has to run the handler
+func (f *cacheFlights) join(key string) (*cacheCall, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c, ok := f.calls[key]; ok {
+		return c, false
+	}
+	c := &cacheCall{}
+	c.wg.Add(1)
+	f.calls[key] = c
+	return c, true
+}
+
+func (f *cacheFlights) done(key string, c *cacheCall) {
+	f.mu.Lock()
+	delete(f.calls, key)
+	f.mu.Unlock()
+	c.wg.Done()
+}
+
+// LRUCacheStore in-memory CacheStore evicting least recently used responses
+type LRUCacheStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+type lruEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+// NewLRUCacheStore creates store holding at most maxEntries responses
+func NewLRUCacheStore(maxEntries int) *LRUCacheStore {
+	return &LRUCacheStore{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// Get returns response of key, expired responses are removed
+func (s *LRUCacheStore) Get(key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*lruEntry)
+	if !time.Now().Before(entry.response.StaleUntil) {
+		s.order.Remove(e)
+		delete(s.entries, key)
+		return nil, false
+	}
+	s.order.MoveToFront(e)
+	return entry.response, true
+}
+
+// Set stores response of key
+func (s *LRUCacheStore) Set(key string, response *CachedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok {
+		e.Value.(*lruEntry).response = response
+		s.order.MoveToFront(e)
+		return
+	}
+	s.entries[key] = s.order.PushFront(&lruEntry{key: key, response: response})
+	for s.maxEntries > 0 && s.order.Len() > s.maxEntries {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Delete removes response of key
+func (s *LRUCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok {
+		s.order.Remove(e)
+		delete(s.entries, key)
+	}
+}
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-slide/slide"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	suite.Suite
+	app   *slide.Slide
+	calls *int32
+}
+
+// h gets the number of the handler call
+func (suite *CacheSuite) setup(config CacheConfig, h func(ctx *slide.Ctx, n int32) error) {
+	calls := new(int32)
+	suite.calls = calls
+	suite.app = slide.InitServer(&slide.Config{})
+	suite.app.Use(Cache(config))
+	suite.app.Get("/", func(ctx *slide.Ctx) error {
+		n := atomic.AddInt32(calls, 1)
+		if h != nil {
+			if err := h(ctx, n); err != nil {
+				return err
+			}
+		}
+		return ctx.Send(http.StatusOK, string(rune('0'+n)))
+	})
+}
+
+func (suite *CacheSuite) request(headers map[string]string) (string, string) {
+	r, err := http.NewRequest(slide.GET, "http://test/", nil)
+	if !assert.Nil(suite.T(), err) {
+		return "", ""
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := testServer(r, suite.app)
+	if !assert.Nil(suite.T(), err) {
+		return "", ""
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res.Header.Get(headerXCache), string(body)
+}
+
+func (suite *CacheSuite) TestHitAndMiss() {
+	suite.setup(CacheConfig{}, nil)
+	status, body := suite.request(nil)
+	assert.Equal(suite.T(), "MISS", status)
+	assert.Equal(suite.T(), "1", body)
+	status, body = suite.request(nil)
+	assert.Equal(suite.T(), "HIT", status)
+	assert.Equal(suite.T(), "1", body)
+
+	_, body = suite.request(map[string]string{slide.HeaderCacheControl: "no-cache"})
+	assert.Equal(suite.T(), "2", body, "no-cache runs the handler and stores the response")
+	_, body = suite.request(nil)
+	assert.Equal(suite.T(), "2", body)
+}
+
+func (suite *CacheSuite) TestNotStored() {
+	tests := map[string]func(ctx *slide.Ctx, n int32) error{
+		"no-store": func(ctx *slide.Ctx, n int32) error {
+			ctx.RequestCtx.Response.Header.Set(slide.HeaderCacheControl, "no-store")
+			return nil
+		},
+		"private": func(ctx *slide.Ctx, n int32) error {
+			ctx.RequestCtx.Response.Header.Set(slide.HeaderCacheControl, "private, max-age=60")
+			return nil
+		},
+		"cookie": func(ctx *slide.Ctx, n int32) error {
+			ctx.SetCookie(&slide.Cookie{Name: "session", Value: "1"})
+			return nil
+		},
+	}
+	for name, h := range tests {
+		suite.setup(CacheConfig{}, h)
+		suite.request(nil)
+		_, body := suite.request(nil)
+		assert.Equal(suite.T(), "2", body, name)
+	}
+}
+
+func (suite *CacheSuite) TestVary() {
+	varyOn := func(name string) func(ctx *slide.Ctx, n int32) error {
+		return func(ctx *slide.Ctx, n int32) error {
+			ctx.Vary(name)
+			return nil
+		}
+	}
+	suite.setup(CacheConfig{}, varyOn(slide.HeaderAcceptEncoding))
+	suite.request(map[string]string{slide.HeaderAcceptEncoding: "gzip"})
+	_, body := suite.request(map[string]string{slide.HeaderAcceptEncoding: "br"})
+	assert.Equal(suite.T(), "2", body, "header outside of the key is not stored")
+
+	suite.setup(CacheConfig{}, varyOn("*"))
+	suite.request(nil)
+	_, body = suite.request(nil)
+	assert.Equal(suite.T(), "2", body)
+
+	suite.setup(CacheConfig{KeyHeaders: []string{"accept-encoding"}}, varyOn(slide.HeaderAcceptEncoding))
+	suite.request(map[string]string{slide.HeaderAcceptEncoding: "gzip"})
+	_, body = suite.request(map[string]string{slide.HeaderAcceptEncoding: "br"})
+	assert.Equal(suite.T(), "2", body)
+	status, body := suite.request(map[string]string{slide.HeaderAcceptEncoding: "gzip"})
+	assert.Equal(suite.T(), "HIT", status)
+	assert.Equal(suite.T(), "1", body)
+}
+
+func (suite *CacheSuite) TestAuthorization() {
+	suite.setup(CacheConfig{}, nil)
+	suite.request(map[string]string{slide.HeaderAuthorization: "Bearer a"})
+	_, body := suite.request(nil)
+	assert.Equal(suite.T(), "2", body, "authorized responses are not shared")
+
+	suite.setup(CacheConfig{}, func(ctx *slide.Ctx, n int32) error {
+		ctx.RequestCtx.Response.Header.Set(slide.HeaderCacheControl, "public, max-age=60")
+		return nil
+	})
+	suite.request(map[string]string{slide.HeaderAuthorization: "Bearer a"})
+	_, body = suite.request(nil)
+	assert.Equal(suite.T(), "1", body)
+}
+
+func (suite *CacheSuite) TestStale() {
+	revalidated := make(chan http.Header, 1)
+	suite.setup(CacheConfig{}, func(ctx *slide.Ctx, n int32) error {
+		ctx.RequestCtx.Response.Header.Set(slide.HeaderCacheControl, "max-age=0, stale-while-revalidate=60")
+		if n == 2 {
+			header := http.Header{}
+			ctx.RequestCtx.Request.Header.VisitAll(func(key, value []byte) {
+				header.Add(string(key), string(value))
+			})
+			revalidated <- header
+		}
+		return nil
+	})
+	suite.request(nil)
+	status, body := suite.request(map[string]string{
+		slide.HeaderAuthorization: "Bearer a",
+		slide.HeaderCookie:        "session=1",
+		slide.HeaderIfNoneMatch:   `"1"`,
+	})
+	assert.Equal(suite.T(), "STALE", status)
+	assert.Equal(suite.T(), "1", body)
+	select {
+	case header := <-revalidated:
+		assert.Empty(suite.T(), header.Get(slide.HeaderAuthorization))
+		assert.Empty(suite.T(), header.Get(slide.HeaderCookie))
+		assert.Empty(suite.T(), header.Get(slide.HeaderIfNoneMatch))
+	case <-time.After(5 * time.Second):
+		suite.T().Fatal("stale response was not revalidated")
+	}
+	assert.Eventually(suite.T(), func() bool {
+		_, body := suite.request(nil)
+		return body == "2"
+	}, 5*time.Second, 10*time.Millisecond, "revalidated response is stored")
+
+	// keys with credentials are refreshed in the foreground
+	suite.setup(CacheConfig{KeyHeaders: []string{slide.HeaderCookie}}, func(ctx *slide.Ctx, n int32) error {
+		ctx.RequestCtx.Response.Header.Set(slide.HeaderCacheControl, "max-age=0, stale-while-revalidate=60")
+		return nil
+	})
+	suite.request(map[string]string{slide.HeaderCookie: "session=1"})
+	status, body = suite.request(map[string]string{slide.HeaderCookie: "session=1"})
+	assert.Equal(suite.T(), "MISS", status)
+	assert.Equal(suite.T(), "2", body)
+}
+
+func (suite *CacheSuite) TestCoalescing() {
+	release := make(chan struct{})
+	suite.setup(CacheConfig{}, func(ctx *slide.Ctx, n int32) error {
+		<-release
+		return nil
+	})
+	var wg sync.WaitGroup
+	bodies := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, body := suite.request(nil)
+			bodies <- body
+		}()
+	}
+	// lets the requests reach the cache before the first one completes
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(bodies)
+	assert.Equal(suite.T(), int32(1), atomic.LoadInt32(suite.calls))
+	for body := range bodies {
+		assert.Equal(suite.T(), "1", body)
+	}
+}
+
+func TestCache(t *testing.T) {
+	suite.Run(t, new(CacheSuite))
+}
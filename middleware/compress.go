@@ -1,16 +1,262 @@
 package middleware
 
 import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
 	"github.com/go-slide/slide"
-	"github.com/valyala/fasthttp"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+)
+
+// supported content encodings
+const (
+	EncodingBrotli  = "br"
+	EncodingZstd    = "zstd"
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
 )
 
-// Compress brotli compression
+// compression levels, mapped to the closest level of each encoding
+const (
+	CompressLevelDefault = iota
+	CompressLevelBestSpeed
+	CompressLevelBestCompression
+)
+
+// CompressConfig configuration for Compress middleware
+type CompressConfig struct {
+	// Encodings supported encodings in server preference order, used when
+	// client accepts several with same quality. defaults to br, zstd, gzip, deflate
+	Encodings []string
+	// Level one of CompressLevelDefault, CompressLevelBestSpeed or CompressLevelBestCompression
+	Level int
+	// MinLength responses smaller than this are sent as is, defaults to 1024
+	// bytes, negative compresses responses of any size
+	MinLength int
+	// ContentTypes compressible content types, entries ending with "/" match the type,
+	// entries starting with "+" match the suffix, others the full media type
+	ContentTypes []string
+	// ExcludedContentTypes content types never compressed, same format as ContentTypes
+	ExcludedContentTypes []string
+}
+
+var (
+	// DefaultCompressConfig default config for compress
+	DefaultCompressConfig = CompressConfig{
+		Encodings: []string{EncodingBrotli, EncodingZstd, EncodingGzip, EncodingDeflate},
+		Level:     CompressLevelBestSpeed,
+		MinLength: 1024,
+		ContentTypes: []string{
+			"text/", "application/json", "application/javascript", "application/xml",
+			"application/wasm", "image/svg+xml", "+json", "+xml",
+		},
+		ExcludedContentTypes: []string{"text/event-stream"},
+	}
+)
+
+// Compress compresses responses with default config
 func Compress() func(ctx *slide.Ctx) error {
-	// give user config to use level // TODO
-	compressHandler := fasthttp.CompressHandlerBrotliLevel(func(c *fasthttp.RequestCtx) {}, fasthttp.CompressBrotliBestSpeed, fasthttp.CompressBestSpeed)
+	return CompressWithConfig(DefaultCompressConfig)
+}
+
+// CompressWithConfig compresses responses after the handler has run, encoding
+// is negotiated from Accept-Encoding
+// Reference https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Accept-Encoding
+func CompressWithConfig(config CompressConfig) func(ctx *slide.Ctx) error {
+	if len(config.Encodings) == 0 {
+		config.Encodings = DefaultCompressConfig.Encodings
+	}
+	if config.MinLength == 0 {
+		config.MinLength = DefaultCompressConfig.MinLength
+	}
+	if len(config.ContentTypes) == 0 {
+		config.ContentTypes = DefaultCompressConfig.ContentTypes
+	}
+	if config.ExcludedContentTypes == nil {
+		config.ExcludedContentTypes = DefaultCompressConfig.ExcludedContentTypes
+	}
+	encoders := map[string]*encoder{}
+	for _, name := range config.Encodings {
+		encoders[name] = newEncoder(name, config.Level)
+	}
 	return func(ctx *slide.Ctx) error {
-		compressHandler(ctx.RequestCtx)
-		return ctx.Next()
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+		response := &ctx.RequestCtx.Response
+		contentType := string(response.Header.ContentType())
+		if !matchContentType(contentType, config.ContentTypes) || matchContentType(contentType, config.ExcludedContentTypes) {
+			return nil
+		}
+		// response depends on Accept-Encoding from here on
+		ctx.Vary(slide.HeaderAcceptEncoding)
+		if len(response.Header.Peek(slide.HeaderContentEncoding)) > 0 || response.IsBodyStream() ||
+			ctx.RequestCtx.IsHead() || response.StatusCode() < http.StatusOK ||
+			response.StatusCode() == http.StatusNoContent || response.StatusCode() == http.StatusNotModified {
+			return nil
+		}
+		body := response.Body()
+		if len(body) < config.MinLength {
+			return nil
+		}
+		name := negotiateEncoding(string(ctx.RequestCtx.Request.Header.Peek(slide.HeaderAcceptEncoding)), config.Encodings)
+		if name == "" {
+			return nil
+		}
+		compressed, err := encoders[name].encode(body)
+		if err != nil {
+			return err
+		}
+		response.SetBodyRaw(compressed)
+		response.Header.Set(slide.HeaderContentEncoding, name)
+		return nil
+	}
+}
+
+func matchContentType(contentType string, patterns []string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	if mediaType == "" {
+		return false
+	}
+	for _, p := range patterns {
+		switch {
+		case strings.HasSuffix(p, "/"):
+			if strings.HasPrefix(mediaType, p) {
+				return true
+			}
+		case strings.HasPrefix(p, "+"):
+			if strings.HasSuffix(mediaType, p) {
+				return true
+			}
+		case mediaType == p:
+			return true
+		}
+	}
+	return false
+}
+
+type acceptedEncoding struct {
+	name    string
+	quality float64
+}
+
+// picks encoding with highest q value, ties are broken by server preference,
+// empty if client accepts none of supported encodings
+func negotiateEncoding(header string, supported []string) string {
+	if header == "" {
+		return ""
+	}
+	qualities := map[string]float64{}
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if name == "*" {
+			wildcard = q
+			continue
+		}
+		qualities[name] = q
+	}
+	var candidates []acceptedEncoding
+	for _, name := range supported {
+		q, ok := qualities[name]
+		if !ok {
+			q = wildcard
+		}
+		if q > 0 {
+			candidates = append(candidates, acceptedEncoding{name: name, quality: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].name
+}
+
+// encoder pooled writer of one encoding
+type encoder struct {
+	pool sync.Pool
+	zstd *zstd.Encoder
+}
+
+type resetWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+func newEncoder(name string, level int) *encoder {
+	e := &encoder{}
+	switch name {
+	case EncodingGzip:
+		gzipLevel := levelFor(level, gzip.DefaultCompression, gzip.BestSpeed, gzip.BestCompression)
+		e.pool.New = func() interface{} {
+			w, _ := gzip.NewWriterLevel(nil, gzipLevel)
+			return w
+		}
+	case EncodingDeflate:
+		zlibLevel := levelFor(level, zlib.DefaultCompression, zlib.BestSpeed, zlib.BestCompression)
+		e.pool.New = func() interface{} {
+			w, _ := zlib.NewWriterLevel(nil, zlibLevel)
+			return w
+		}
+	case EncodingBrotli:
+		brotliLevel := levelFor(level, brotli.DefaultCompression, brotli.BestSpeed, brotli.BestCompression)
+		e.pool.New = func() interface{} {
+			return brotli.NewWriterLevel(nil, brotliLevel)
+		}
+	case EncodingZstd:
+		zstdLevel := zstd.EncoderLevel(levelFor(level, int(zstd.SpeedDefault), int(zstd.SpeedFastest), int(zstd.SpeedBetterCompression)))
+		// EncodeAll is safe for concurrent use
+		e.zstd, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstdLevel))
+	default:
+		panic("compress: unsupported encoding " + name)
+	}
+	return e
+}
+
+func levelFor(level, def, speed, best int) int {
+	switch level {
+	case CompressLevelBestSpeed:
+		return speed
+	case CompressLevelBestCompression:
+		return best
+	}
+	return def
+}
+
+func (e *encoder) encode(body []byte) ([]byte, error) {
+	if e.zstd != nil {
+		return e.zstd.EncodeAll(body, nil), nil
+	}
+	var buf bytes.Buffer
+	w := e.pool.Get().(resetWriter)
+	defer e.pool.Put(w)
+	w.Reset(&buf)
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
 }
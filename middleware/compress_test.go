@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-slide/slide"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CompressSuite struct {
+	suite.Suite
+}
+
+var compressBody = strings.Repeat("slide compresses responses. ", 100)
+
+func (suite *CompressSuite) request(mw func(ctx *slide.Ctx) error, acceptEncoding string, h func(ctx *slide.Ctx) error) (*http.Response, []byte) {
+	app := slide.InitServer(&slide.Config{})
+	app.Use(mw)
+	app.Get("/", h)
+	r, err := http.NewRequest(slide.GET, "http://test/", nil)
+	if !assert.Nil(suite.T(), err) {
+		return &http.Response{}, nil
+	}
+	// stops the transport from negotiating and decoding gzip itself
+	r.Header.Set(slide.HeaderAcceptEncoding, acceptEncoding)
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return &http.Response{}, nil
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res, body
+}
+
+func sendText(body string) func(ctx *slide.Ctx) error {
+	return func(ctx *slide.Ctx) error {
+		return ctx.Send(http.StatusOK, body)
+	}
+}
+
+func decode(encoding string, body []byte) (string, error) {
+	var r io.Reader
+	var err error
+	switch encoding {
+	case EncodingGzip:
+		r, err = gzip.NewReader(bytes.NewReader(body))
+	case EncodingDeflate:
+		r, err = zlib.NewReader(bytes.NewReader(body))
+	case EncodingBrotli:
+		r = brotli.NewReader(bytes.NewReader(body))
+	case EncodingZstd:
+		var d *zstd.Decoder
+		d, err = zstd.NewReader(bytes.NewReader(body))
+		if err == nil {
+			defer d.Close()
+			r = d
+		}
+	default:
+		return string(body), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	decoded, err := ioutil.ReadAll(r)
+	return string(decoded), err
+}
+
+func (suite *CompressSuite) TestNegotiation() {
+	tests := []struct {
+		acceptEncoding string
+		encoding       string
+	}{
+		{"gzip", EncodingGzip},
+		{"deflate", EncodingDeflate},
+		{"br", EncodingBrotli},
+		{"zstd", EncodingZstd},
+		{"gzip, br", EncodingBrotli},
+		{"gzip;q=1, br;q=0.5", EncodingGzip},
+		{"*", EncodingBrotli},
+		{"br;q=0, *", EncodingZstd},
+		{"identity", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		res, body := suite.request(Compress(), test.acceptEncoding, sendText(compressBody))
+		assert.Equal(suite.T(), test.encoding, res.Header.Get(slide.HeaderContentEncoding), test.acceptEncoding)
+		assert.Equal(suite.T(), slide.HeaderAcceptEncoding, res.Header.Get(slide.HeaderVary), test.acceptEncoding)
+		decoded, err := decode(test.encoding, body)
+		assert.Nil(suite.T(), err, test.acceptEncoding)
+		assert.Equal(suite.T(), compressBody, decoded, test.acceptEncoding)
+	}
+}
+
+func (suite *CompressSuite) TestSkipped() {
+	res, _ := suite.request(Compress(), "gzip", sendText("short"))
+	assert.Empty(suite.T(), res.Header.Get(slide.HeaderContentEncoding), "below MinLength")
+
+	res, _ = suite.request(Compress(), "gzip", func(ctx *slide.Ctx) error {
+		return ctx.Blob(http.StatusOK, "image/png", []byte(compressBody))
+	})
+	assert.Empty(suite.T(), res.Header.Get(slide.HeaderContentEncoding), "content type")
+	assert.Empty(suite.T(), res.Header.Get(slide.HeaderVary))
+
+	res, body := suite.request(Compress(), "gzip", func(ctx *slide.Ctx) error {
+		ctx.RequestCtx.Response.Header.Set(slide.HeaderContentEncoding, "custom")
+		return ctx.Send(http.StatusOK, compressBody)
+	})
+	assert.Equal(suite.T(), "custom", res.Header.Get(slide.HeaderContentEncoding), "already encoded")
+	assert.Equal(suite.T(), compressBody, string(body))
+}
+
+func (suite *CompressSuite) TestMinLength() {
+	res, body := suite.request(CompressWithConfig(CompressConfig{MinLength: -1}), "gzip", sendText("short"))
+	assert.Equal(suite.T(), EncodingGzip, res.Header.Get(slide.HeaderContentEncoding))
+	decoded, err := decode(EncodingGzip, body)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "short", decoded)
+
+	res, _ = suite.request(CompressWithConfig(CompressConfig{MinLength: 10000}), "gzip", sendText(compressBody))
+	assert.Empty(suite.T(), res.Header.Get(slide.HeaderContentEncoding))
+}
+
+func TestCompress(t *testing.T) {
+	suite.Run(t, new(CompressSuite))
+}
@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -10,12 +12,22 @@ import (
 
 // CorsConfig configuration for Corsfeat
 type CorsConfig struct {
-	AllowMethods     []string
-	AllowOrigins     []string
+	AllowMethods []string
+	// AllowOrigins allowed origins, "*" allows any origin and a "*" label
+	// allows any subdomain, ex "https://*.example.com"
+	AllowOrigins []string
+	// AllowOriginPatterns regular expressions matched against the whole
+	// lower cased origin, ex `https://[a-z]+\.example\.com`
+	AllowOriginPatterns []string
+	// AllowOriginFunc custom origin check, used when origin matched nothing else
+	AllowOriginFunc  func(origin string) bool
 	AllowHeaders     []string
 	AllowCredentials bool
 	ExposeHeaders    []string
 	MaxAge           int
+	// AllowPrivateNetwork answers Private Network Access preflights
+	// Reference https://wicg.github.io/private-network-access/
+	AllowPrivateNetwork bool
 }
 
 var (
@@ -32,64 +44,126 @@ func Cors() func(ctx *slide.Ctx) error {
 	return CorsWithConfig(DefaultCORSConfig)
 }
 
-// CorsWithConfig Cors with a config
+// CorsWithConfig Cors with a config, use it with app.Use, group.Use or as
+// route middleware for per route policies, preflight requests of a route
+// need an Options route with the same middleware
 func CorsWithConfig(config CorsConfig) func(ctx *slide.Ctx) error {
-	return func(ctx *slide.Ctx) error {
-		if len(config.AllowOrigins) == 0 {
-			config.AllowOrigins = DefaultCORSConfig.AllowOrigins
-		}
-		if len(config.AllowMethods) == 0 {
-			config.AllowMethods = DefaultCORSConfig.AllowMethods
+	if len(config.AllowOrigins) == 0 && len(config.AllowOriginPatterns) == 0 && config.AllowOriginFunc == nil {
+		config.AllowOrigins = DefaultCORSConfig.AllowOrigins
+	}
+	if len(config.AllowMethods) == 0 {
+		config.AllowMethods = DefaultCORSConfig.AllowMethods
+	}
+	allowMethods := strings.Join(config.AllowMethods, ",")
+	allowHeaders := strings.Join(config.AllowHeaders, ",")
+	exposeHeaders := strings.Join(config.ExposeHeaders, ",")
+	maxAge := strconv.Itoa(config.MaxAge)
+	allowAll := false
+	exact := map[string]bool{}
+	var patterns []*regexp.Regexp
+	for _, o := range config.AllowOrigins {
+		switch {
+		case o == "*":
+			allowAll = true
+		case strings.Contains(o, "*"):
+			patterns = append(patterns, wildcardOriginPattern(o))
+		default:
+			exact[strings.ToLower(o)] = true
 		}
-		allowMethods := strings.Join(config.AllowMethods, ",")
-		allowHeaders := strings.Join(config.AllowHeaders, ",")
-		exposeHeaders := strings.Join(config.ExposeHeaders, ",")
-		maxAge := strconv.Itoa(config.MaxAge)
-		origin := string(ctx.RequestCtx.Request.Header.Peek(slide.HeaderOrigin))
-		allowOrigin := ""
-		for _, o := range config.AllowOrigins {
-			if o == "*" && config.AllowCredentials {
-				allowOrigin = origin
-				break
+	}
+	for _, p := range config.AllowOriginPatterns {
+		// anchored so "https://example.com" does not match https://example.com.evil.io
+		patterns = append(patterns, regexp.MustCompile("^(?:"+p+")$"))
+	}
+	// returns value of Access-Control-Allow-Origin, empty if origin is not allowed
+	allowOrigin := func(origin string) string {
+		if allowAll {
+			// wildcard is not allowed with credentials, reflect origin instead
+			if config.AllowCredentials {
+				return origin
 			}
-			if o == "*" || o == origin {
-				allowOrigin = o
-				break
+			return "*"
+		}
+		lower := strings.ToLower(origin)
+		if exact[lower] {
+			return origin
+		}
+		for _, p := range patterns {
+			if p.MatchString(lower) {
+				return origin
 			}
 		}
-		if string(ctx.RequestCtx.Method()) != http.MethodOptions {
-			ctx.RequestCtx.Response.Header.Set(slide.HeaderAccessControlAllowOrigin, allowOrigin)
-			ctx.RequestCtx.Response.Header.Set(slide.HeaderVary, slide.HeaderOrigin)
-			if config.AllowCredentials {
-				ctx.RequestCtx.Response.Header.Set(slide.HeaderAccessControlAllowCredentials, "true")
+		if config.AllowOriginFunc != nil && config.AllowOriginFunc(origin) {
+			return origin
+		}
+		return ""
+	}
+	// response only depends on origin when it is not a static wildcard
+	varyOrigin := !allowAll || config.AllowCredentials
+	return func(ctx *slide.Ctx) error {
+		header := &ctx.RequestCtx.Response.Header
+		origin := string(ctx.RequestCtx.Request.Header.Peek(slide.HeaderOrigin))
+		preflight := ctx.RequestCtx.IsOptions() &&
+			len(ctx.RequestCtx.Request.Header.Peek(slide.HeaderAccessControlRequestMethod)) > 0
+		if !preflight {
+			if varyOrigin {
+				ctx.Vary(slide.HeaderOrigin)
 			}
-			if exposeHeaders != "" {
-				ctx.RequestCtx.Response.Header.Set(slide.HeaderAccessControlExposeHeaders, exposeHeaders)
+			if origin == "" {
+				return ctx.Next()
+			}
+			if allowed := allowOrigin(origin); allowed != "" {
+				header.Set(slide.HeaderAccessControlAllowOrigin, allowed)
+				if config.AllowCredentials {
+					header.Set(slide.HeaderAccessControlAllowCredentials, "true")
+				}
+				if exposeHeaders != "" {
+					header.Set(slide.HeaderAccessControlExposeHeaders, exposeHeaders)
+				}
 			}
 			return ctx.Next()
 		}
-		// Options request
-		ctx.RequestCtx.Response.Header.Set(slide.HeaderVary, slide.HeaderOrigin)
-		ctx.RequestCtx.Response.Header.Set(slide.HeaderVary, slide.HeaderAccessControlRequestMethod)
-		ctx.RequestCtx.Response.Header.Set(slide.HeaderVary, slide.HeaderAccessControlRequestHeaders)
-		ctx.RequestCtx.Response.Header.Set(slide.HeaderAccessControlAllowOrigin, allowOrigin)
-		ctx.RequestCtx.Response.Header.Set(slide.HeaderAccessControlAllowMethods, allowMethods)
-
+		// preflight request
+		if varyOrigin {
+			ctx.Vary(slide.HeaderOrigin)
+		}
+		ctx.Vary(slide.HeaderAccessControlRequestMethod)
+		ctx.Vary(slide.HeaderAccessControlRequestHeaders)
+		if config.AllowPrivateNetwork {
+			ctx.Vary(slide.HeaderAccessControlRequestPrivateNetwork)
+		}
+		allowed := allowOrigin(origin)
+		if origin == "" || allowed == "" {
+			return ctx.SendStatusCode(http.StatusNoContent)
+		}
+		header.Set(slide.HeaderAccessControlAllowOrigin, allowed)
+		header.Set(slide.HeaderAccessControlAllowMethods, allowMethods)
 		if config.AllowCredentials {
-			ctx.RequestCtx.Response.Header.Set(slide.HeaderAccessControlAllowCredentials, "true")
+			header.Set(slide.HeaderAccessControlAllowCredentials, "true")
 		}
 		if allowHeaders != "" {
-			ctx.RequestCtx.Response.Header.Set(slide.HeaderAccessControlAllowHeaders, allowHeaders)
+			header.Set(slide.HeaderAccessControlAllowHeaders, allowHeaders)
 		} else {
 			h := string(ctx.RequestCtx.Request.Header.Peek(slide.HeaderAccessControlRequestHeaders))
 			if h != "" {
-				ctx.RequestCtx.Response.Header.Set(slide.HeaderAccessControlAllowHeaders, h)
+				header.Set(slide.HeaderAccessControlAllowHeaders, h)
 			}
 		}
 		if config.MaxAge > 0 {
-			ctx.RequestCtx.Response.Header.Set(slide.HeaderAccessControlMaxAge, maxAge)
+			header.Set(slide.HeaderAccessControlMaxAge, maxAge)
+		}
+		if config.AllowPrivateNetwork &&
+			string(ctx.RequestCtx.Request.Header.Peek(slide.HeaderAccessControlRequestPrivateNetwork)) == "true" {
+			header.Set(slide.HeaderAccessControlAllowPrivateNetwork, "true")
 		}
 		return ctx.SendStatusCode(http.StatusNoContent)
 	}
 
 }
+
+// https://*.example.com matches https://api.example.com and https://a.b.example.com
+func wildcardOriginPattern(origin string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(strings.ToLower(origin))
+	pattern := strings.Replace(quoted, `\*`, `[a-z0-9-]+(\.[a-z0-9-]+)*`, -1)
+	return regexp.MustCompile(fmt.Sprintf("^%s$", pattern))
+}
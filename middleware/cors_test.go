@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-slide/slide"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CorsSuite struct {
+	suite.Suite
+}
+
+func (suite *CorsSuite) request(config CorsConfig, method, origin string, headers map[string]string) *http.Response {
+	app := slide.InitServer(&slide.Config{})
+	api := app.Group("/api")
+	api.Use(CorsWithConfig(config))
+	api.Get("/users", func(ctx *slide.Ctx) error {
+		return ctx.Send(http.StatusOK, "users")
+	})
+	r, err := http.NewRequest(method, "http://test/api/users", nil)
+	if !assert.Nil(suite.T(), err) {
+		return &http.Response{}
+	}
+	if origin != "" {
+		r.Header.Set(slide.HeaderOrigin, origin)
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return &http.Response{}
+	}
+	return res
+}
+
+func (suite *CorsSuite) preflight(config CorsConfig, origin string) *http.Response {
+	return suite.request(config, http.MethodOptions, origin, map[string]string{
+		slide.HeaderAccessControlRequestMethod:  http.MethodPut,
+		slide.HeaderAccessControlRequestHeaders: "X-Token",
+	})
+}
+
+func (suite *CorsSuite) TestDefault() {
+	res := suite.request(DefaultCORSConfig, slide.GET, "https://example.com", nil)
+	assert.Equal(suite.T(), http.StatusOK, res.StatusCode)
+	assert.Equal(suite.T(), "*", res.Header.Get(slide.HeaderAccessControlAllowOrigin))
+	assert.Empty(suite.T(), res.Header.Get(slide.HeaderVary), "wildcard does not depend on origin")
+
+	res = suite.preflight(DefaultCORSConfig, "https://example.com")
+	assert.Equal(suite.T(), http.StatusNoContent, res.StatusCode)
+	assert.Equal(suite.T(), "*", res.Header.Get(slide.HeaderAccessControlAllowOrigin))
+	assert.Equal(suite.T(), "GET,HEAD,PUT,PATCH,POST,DELETE", res.Header.Get(slide.HeaderAccessControlAllowMethods))
+	assert.Equal(suite.T(), "X-Token", res.Header.Get(slide.HeaderAccessControlAllowHeaders))
+	assert.Equal(suite.T(), "Access-Control-Request-Method, Access-Control-Request-Headers", res.Header.Get(slide.HeaderVary))
+}
+
+func (suite *CorsSuite) TestCredentials() {
+	config := CorsConfig{AllowOrigins: []string{"*"}, AllowCredentials: true, ExposeHeaders: []string{"X-Total"}, MaxAge: 600}
+	res := suite.request(config, slide.GET, "https://example.com", nil)
+	assert.Equal(suite.T(), "https://example.com", res.Header.Get(slide.HeaderAccessControlAllowOrigin), "origin is reflected instead of *")
+	assert.Equal(suite.T(), "true", res.Header.Get(slide.HeaderAccessControlAllowCredentials))
+	assert.Equal(suite.T(), "X-Total", res.Header.Get(slide.HeaderAccessControlExposeHeaders))
+	assert.Equal(suite.T(), slide.HeaderOrigin, res.Header.Get(slide.HeaderVary))
+
+	res = suite.preflight(config, "https://example.com")
+	assert.Equal(suite.T(), "https://example.com", res.Header.Get(slide.HeaderAccessControlAllowOrigin))
+	assert.Equal(suite.T(), "true", res.Header.Get(slide.HeaderAccessControlAllowCredentials))
+	assert.Equal(suite.T(), "600", res.Header.Get(slide.HeaderAccessControlMaxAge))
+}
+
+func (suite *CorsSuite) TestOrigins() {
+	config := CorsConfig{
+		AllowOrigins:        []string{"https://example.com", "https://*.example.org"},
+		AllowOriginPatterns: []string{`https://[a-z]+\.example\.net`},
+		AllowOriginFunc: func(origin string) bool {
+			return origin == "https://func.io"
+		},
+	}
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://example.com", true},
+		{"https://EXAMPLE.com", true},
+		{"http://example.com", false},
+		{"https://api.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"https://api.example.org.evil.io", false},
+		{"https://api.example.net", true},
+		{"https://api.example.net.evil.io", false},
+		{"https://evil.io/https://api.example.net", false},
+		{"https://func.io", true},
+		{"https://other.io", false},
+	}
+	for _, test := range tests {
+		res := suite.request(config, slide.GET, test.origin, nil)
+		assert.Equal(suite.T(), http.StatusOK, res.StatusCode, test.origin)
+		assert.Equal(suite.T(), slide.HeaderOrigin, res.Header.Get(slide.HeaderVary), test.origin)
+		expected := ""
+		if test.allowed {
+			expected = test.origin
+		}
+		assert.Equal(suite.T(), expected, res.Header.Get(slide.HeaderAccessControlAllowOrigin), test.origin)
+
+		res = suite.preflight(config, test.origin)
+		assert.Equal(suite.T(), http.StatusNoContent, res.StatusCode, test.origin)
+		assert.Equal(suite.T(), expected, res.Header.Get(slide.HeaderAccessControlAllowOrigin), test.origin)
+		if !test.allowed {
+			assert.Empty(suite.T(), res.Header.Get(slide.HeaderAccessControlAllowMethods), test.origin)
+		}
+	}
+}
+
+func (suite *CorsSuite) TestPrivateNetwork() {
+	res := suite.request(CorsConfig{AllowPrivateNetwork: true}, http.MethodOptions, "https://example.com", map[string]string{
+		slide.HeaderAccessControlRequestMethod:         slide.GET,
+		slide.HeaderAccessControlRequestPrivateNetwork: "true",
+	})
+	assert.Equal(suite.T(), "true", res.Header.Get(slide.HeaderAccessControlAllowPrivateNetwork))
+}
+
+func TestCors(t *testing.T) {
+	suite.Run(t, new(CorsSuite))
+}
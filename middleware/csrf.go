@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-slide/slide"
+)
+
+// csrf patterns
+const (
+	// CSRFDoubleSubmit token is kept in a cookie and has to be echoed back by the client
+	CSRFDoubleSubmit = "double-submit"
+	// CSRFSynchronizer token is kept server side in CSRFConfig.TokenStore
+	CSRFSynchronizer = "synchronizer"
+)
+
+// CSRFTokenStore server side storage of csrf tokens for synchronizer pattern,
+// usually backed by the user session
+type CSRFTokenStore interface {
+	// Load returns stored token, empty if none
+	Load(ctx *slide.Ctx) (string, error)
+	// Save stores token for following requests
+	Save(ctx *slide.Ctx, token string) error
+}
+
+// CSRFConfig configuration for CSRF middleware
+type CSRFConfig struct {
+	// Pattern CSRFDoubleSubmit or CSRFSynchronizer, defaults to CSRFDoubleSubmit
+	Pattern string
+	// TokenStore storage for CSRFSynchronizer, required for that pattern
+	TokenStore CSRFTokenStore
+	// TokenLength number of random bytes in token, defaults to 32
+	TokenLength int
+	// TokenLookup where to find the submitted token, "<source>:<name>" separated by comma,
+	// source can be header, form or query. defaults to "header:X-CSRF-Token,form:_csrf"
+	TokenLookup string
+	// CookieName name of double submit cookie, defaults to "_csrf"
+	CookieName     string
+	CookieDomain   string
+	CookiePath     string
+	CookieMaxAge   time.Duration
+	CookieSecure   bool
+	CookieHTTPOnly bool
+	// CookieSameSite defaults to lax mode
+	CookieSameSite slide.SameSite
+	// SafeMethods methods which are not checked,
+	// defaults to GET, HEAD, OPTIONS and TRACE
+	SafeMethods []string
+}
+
+var (
+	// DefaultCSRFConfig default config for csrf
+	DefaultCSRFConfig = CSRFConfig{
+		Pattern:        CSRFDoubleSubmit,
+		TokenLength:    32,
+		TokenLookup:    "header:" + slide.HeaderXCSRFToken + ",form:_csrf",
+		CookieName:     "_csrf",
+		CookiePath:     "/",
+		CookieMaxAge:   24 * time.Hour,
+		CookieSameSite: slide.SameSiteLax,
+		SafeMethods:    []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace},
+	}
+
+	// ErrCSRFMissing unsafe request without csrf token
+	ErrCSRFMissing = slide.NewError(http.StatusForbidden, "missing csrf token")
+	// ErrCSRFInvalid submitted csrf token does not match
+	ErrCSRFInvalid = slide.NewError(http.StatusForbidden, "invalid csrf token")
+)
+
+// CSRF protection middleware, token of current request is available
+// to handlers and templates with ctx.CSRFToken()
+// Reference https://cheatsheetseries.owasp.org/cheatsheets/Cross-Site_Request_Forgery_Prevention_Cheat_Sheet.html
+func CSRF(config CSRFConfig) func(ctx *slide.Ctx) error {
+	if config.Pattern == "" {
+		config.Pattern = DefaultCSRFConfig.Pattern
+	}
+	if config.TokenLength == 0 {
+		config.TokenLength = DefaultCSRFConfig.TokenLength
+	}
+	if config.TokenLookup == "" {
+		config.TokenLookup = DefaultCSRFConfig.TokenLookup
+	}
+	if config.CookieName == "" {
+		config.CookieName = DefaultCSRFConfig.CookieName
+	}
+	if config.CookiePath == "" {
+		config.CookiePath = DefaultCSRFConfig.CookiePath
+	}
+	if config.CookieMaxAge == 0 {
+		config.CookieMaxAge = DefaultCSRFConfig.CookieMaxAge
+	}
+	if config.CookieSameSite == slide.SameSiteDefault {
+		config.CookieSameSite = DefaultCSRFConfig.CookieSameSite
+	}
+	if len(config.SafeMethods) == 0 {
+		config.SafeMethods = DefaultCSRFConfig.SafeMethods
+	}
+	if config.Pattern != CSRFDoubleSubmit && config.Pattern != CSRFSynchronizer {
+		panic(errors.New("csrf: unknown pattern " + config.Pattern))
+	}
+	if config.Pattern == CSRFSynchronizer && config.TokenStore == nil {
+		panic(errors.New("csrf: synchronizer pattern requires TokenStore"))
+	}
+	safeMethods := map[string]bool{}
+	for _, m := range config.SafeMethods {
+		safeMethods[m] = true
+	}
+	extract := createExtractor(config.TokenLookup, "")
+	return func(ctx *slide.Ctx) error {
+		token, err := loadCSRFToken(ctx, &config)
+		if err != nil {
+			return err
+		}
+		if !safeMethods[string(ctx.RequestCtx.Method())] {
+			submitted := extract(ctx)
+			if submitted == "" {
+				return ErrCSRFMissing
+			}
+			if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(submitted)) != 1 {
+				return ErrCSRFInvalid
+			}
+		}
+		if token == "" {
+			if token, err = generateToken(config.TokenLength); err != nil {
+				return err
+			}
+			if config.Pattern == CSRFSynchronizer {
+				if err := config.TokenStore.Save(ctx, token); err != nil {
+					return err
+				}
+			}
+		}
+		if config.Pattern == CSRFDoubleSubmit {
+			setCSRFCookie(ctx, &config, token)
+		}
+		ctx.Set(slide.CSRFContextKey, token)
+		return ctx.Next()
+	}
+}
+
+func loadCSRFToken(ctx *slide.Ctx, config *CSRFConfig) (string, error) {
+	if config.Pattern == CSRFSynchronizer {
+		return config.TokenStore.Load(ctx)
+	}
+	return ctx.Cookie(config.CookieName), nil
+}
+
+func setCSRFCookie(ctx *slide.Ctx, config *CSRFConfig, token string) {
+	ctx.SetCookie(&slide.Cookie{
+		Name:     config.CookieName,
+		Value:    token,
+		Domain:   config.CookieDomain,
+		Path:     config.CookiePath,
+		MaxAge:   int(config.CookieMaxAge / time.Second),
+		Secure:   config.CookieSecure,
+		HTTPOnly: config.CookieHTTPOnly,
+		SameSite: config.CookieSameSite,
+	})
+	ctx.Vary(slide.HeaderCookie)
+}
+
+// random url safe token
+func generateToken(length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
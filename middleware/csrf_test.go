@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-slide/slide"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CSRFSuite struct {
+	suite.Suite
+	app *slide.Slide
+}
+
+// keeps the synchronizer token of the single test client
+type memoryCSRFStore struct {
+	token string
+}
+
+func (s *memoryCSRFStore) Load(ctx *slide.Ctx) (string, error) {
+	return s.token, nil
+}
+
+func (s *memoryCSRFStore) Save(ctx *slide.Ctx, token string) error {
+	s.token = token
+	return nil
+}
+
+func (suite *CSRFSuite) setup(config CSRFConfig) {
+	suite.app = slide.InitServer(&slide.Config{})
+	suite.app.Use(CSRF(config))
+	handler := func(ctx *slide.Ctx) error {
+		return ctx.Send(http.StatusOK, ctx.CSRFToken())
+	}
+	suite.app.Get("/form", handler)
+	suite.app.Post("/form", handler)
+}
+
+func (suite *CSRFSuite) request(r *http.Request) (*http.Response, string) {
+	res, err := testServer(r, suite.app)
+	if !assert.Nil(suite.T(), err) {
+		return &http.Response{}, ""
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res, string(body)
+}
+
+func (suite *CSRFSuite) post(cookie, header string, form url.Values) int {
+	r, err := http.NewRequest(slide.POST, "http://test/form", strings.NewReader(form.Encode()))
+	if !assert.Nil(suite.T(), err) {
+		return 0
+	}
+	r.Header.Set(slide.ContentType, slide.ApplicationForm)
+	if cookie != "" {
+		r.AddCookie(&http.Cookie{Name: "_csrf", Value: cookie})
+	}
+	if header != "" {
+		r.Header.Set(slide.HeaderXCSRFToken, header)
+	}
+	res, _ := suite.request(r)
+	return res.StatusCode
+}
+
+func (suite *CSRFSuite) TestDoubleSubmit() {
+	suite.setup(CSRFConfig{})
+	r, _ := http.NewRequest(slide.GET, "http://test/form", nil)
+	res, token := suite.request(r)
+	assert.Equal(suite.T(), http.StatusOK, res.StatusCode)
+	assert.NotEmpty(suite.T(), token)
+	cookies := res.Cookies()
+	if assert.Len(suite.T(), cookies, 1) {
+		assert.Equal(suite.T(), "_csrf", cookies[0].Name)
+		assert.Equal(suite.T(), token, cookies[0].Value)
+		assert.Equal(suite.T(), http.SameSiteLaxMode, cookies[0].SameSite)
+	}
+
+	assert.Equal(suite.T(), http.StatusOK, suite.post(token, token, nil), "header")
+	assert.Equal(suite.T(), http.StatusOK, suite.post(token, "", url.Values{"_csrf": {token}}), "form")
+	assert.Equal(suite.T(), http.StatusForbidden, suite.post(token, "", nil), "missing token")
+	assert.Equal(suite.T(), http.StatusForbidden, suite.post(token, token+"x", nil), "other token")
+	assert.Equal(suite.T(), http.StatusForbidden, suite.post("", token, nil), "missing cookie")
+}
+
+func (suite *CSRFSuite) TestSynchronizer() {
+	store := &memoryCSRFStore{}
+	suite.setup(CSRFConfig{Pattern: CSRFSynchronizer, TokenStore: store})
+	r, _ := http.NewRequest(slide.GET, "http://test/form", nil)
+	res, token := suite.request(r)
+	assert.Equal(suite.T(), token, store.token)
+	assert.Empty(suite.T(), res.Cookies())
+
+	assert.Equal(suite.T(), http.StatusOK, suite.post("", token, nil))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.post("", "forged", nil))
+}
+
+func TestCSRF(t *testing.T) {
+	suite.Run(t, new(CSRFSuite))
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-slide/slide"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DecompressConfig configuration for Decompress middleware
+type DecompressConfig struct {
+	// MaxSize maximum size of decompressed body in bytes, defaults to 10MB,
+	// larger bodies get slide.ErrBodyTooLarge
+	MaxSize int64
+}
+
+var (
+	// DefaultDecompressConfig default config for decompress
+	DefaultDecompressConfig = DecompressConfig{
+		MaxSize: 10 << 20,
+	}
+
+	// ErrUnsupportedEncoding request body encoding is not supported
+	ErrUnsupportedEncoding = slide.NewError(http.StatusUnsupportedMediaType, "unsupported content encoding")
+	// ErrInvalidEncodedBody request body could not be decoded
+	ErrInvalidEncodedBody = slide.NewError(http.StatusBadRequest, "invalid encoded body")
+)
+
+// Decompress decodes gzip, deflate, br and zstd encoded request bodies
+// based on Content-Encoding, so ctx.Bind reads plain bodies
+func Decompress(config DecompressConfig) func(ctx *slide.Ctx) error {
+	if config.MaxSize == 0 {
+		config.MaxSize = DefaultDecompressConfig.MaxSize
+	}
+	return func(ctx *slide.Ctx) error {
+		request := &ctx.RequestCtx.Request
+		header := string(request.Header.Peek(slide.HeaderContentEncoding))
+		if header == "" {
+			return ctx.Next()
+		}
+		var encodings []string
+		for _, e := range strings.Split(header, ",") {
+			e = strings.ToLower(strings.TrimSpace(e))
+			if e == "" || e == "identity" {
+				continue
+			}
+			if !isSupportedEncoding(e) {
+				// tell client what it can use, https://tools.ietf.org/html/rfc7694
+				ctx.RequestCtx.Response.Header.Set(slide.HeaderAcceptEncoding, supportedRequestEncodings)
+				return ErrUnsupportedEncoding
+			}
+			encodings = append(encodings, e)
+		}
+		body, err := ctx.Body()
+		if err != nil {
+			return err
+		}
+		// encodings are listed in the order they were applied
+		for i := len(encodings) - 1; i >= 0; i-- {
+			decoded, err := decodeBody(encodings[i], body, config.MaxSize)
+			if err != nil {
+				return err
+			}
+			body = decoded
+		}
+		request.SetBody(body)
+		request.Header.Del(slide.HeaderContentEncoding)
+		return ctx.Next()
+	}
+}
+
+const supportedRequestEncodings = EncodingGzip + ", " + EncodingDeflate + ", " + EncodingBrotli + ", " + EncodingZstd
+
+func isSupportedEncoding(e string) bool {
+	switch e {
+	case EncodingGzip, EncodingDeflate, EncodingBrotli, EncodingZstd:
+		return true
+	}
+	return false
+}
+
+func decodeBody(encoding string, body []byte, maxSize int64) ([]byte, error) {
+	var r io.Reader
+	src := bytes.NewReader(body)
+	switch encoding {
+	case EncodingGzip:
+		gr, err := gzip.NewReader(src)
+		if err != nil {
+			return nil, ErrInvalidEncodedBody.WithInternal(err)
+		}
+		defer gr.Close()
+		r = gr
+	case EncodingDeflate:
+		zr, err := zlib.NewReader(src)
+		if err != nil {
+			return nil, ErrInvalidEncodedBody.WithInternal(err)
+		}
+		defer zr.Close()
+		r = zr
+	case EncodingBrotli:
+		r = brotli.NewReader(src)
+	case EncodingZstd:
+		zr, err := zstd.NewReader(src, zstd.WithDecoderLowmem(true))
+		if err != nil {
+			return nil, ErrInvalidEncodedBody.WithInternal(err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	// read one byte past the limit to detect oversized bodies
+	decoded, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, ErrInvalidEncodedBody.WithInternal(err)
+	}
+	if int64(len(decoded)) > maxSize {
+		return nil, slide.ErrBodyTooLarge
+	}
+	return decoded, nil
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-slide/slide"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DecompressSuite struct {
+	suite.Suite
+}
+
+func encode(encoding, body string) []byte {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case EncodingGzip:
+		w = gzip.NewWriter(&buf)
+	case EncodingDeflate:
+		w = zlib.NewWriter(&buf)
+	case EncodingBrotli:
+		w = brotli.NewWriter(&buf)
+	case EncodingZstd:
+		w, _ = zstd.NewWriter(&buf)
+	}
+	_, _ = w.Write([]byte(body))
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+func (suite *DecompressSuite) request(config DecompressConfig, encoding string, body []byte) (*http.Response, string) {
+	app := slide.InitServer(&slide.Config{})
+	app.Use(Decompress(config))
+	app.Post("/", func(ctx *slide.Ctx) error {
+		return ctx.Send(http.StatusOK, string(ctx.RequestCtx.Request.Body()))
+	})
+	r, err := http.NewRequest(slide.POST, "http://test/", bytes.NewReader(body))
+	if !assert.Nil(suite.T(), err) {
+		return &http.Response{}, ""
+	}
+	r.Header.Set(slide.HeaderContentEncoding, encoding)
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return &http.Response{}, ""
+	}
+	response, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res, string(response)
+}
+
+func (suite *DecompressSuite) TestEncodings() {
+	for _, encoding := range []string{EncodingGzip, EncodingDeflate, EncodingBrotli, EncodingZstd} {
+		res, body := suite.request(DecompressConfig{}, encoding, encode(encoding, `{"name":"slide"}`))
+		assert.Equal(suite.T(), http.StatusOK, res.StatusCode, encoding)
+		assert.Equal(suite.T(), `{"name":"slide"}`, body, encoding)
+	}
+	// encodings are removed in reverse order
+	twice := encode(EncodingBrotli, string(encode(EncodingGzip, "slide")))
+	_, body := suite.request(DecompressConfig{}, "gzip, br", twice)
+	assert.Equal(suite.T(), "slide", body)
+
+	_, body = suite.request(DecompressConfig{}, "identity", []byte("slide"))
+	assert.Equal(suite.T(), "slide", body)
+}
+
+func (suite *DecompressSuite) TestBomb() {
+	bomb := encode(EncodingGzip, strings.Repeat("a", 1<<20))
+	res, _ := suite.request(DecompressConfig{MaxSize: 1 << 10}, EncodingGzip, bomb)
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, res.StatusCode)
+
+	res, _ = suite.request(DecompressConfig{MaxSize: 1 << 20}, EncodingGzip, bomb)
+	assert.Equal(suite.T(), http.StatusOK, res.StatusCode, "body of exactly MaxSize")
+}
+
+func (suite *DecompressSuite) TestBadEncoding() {
+	res, _ := suite.request(DecompressConfig{}, "compress", []byte("slide"))
+	assert.Equal(suite.T(), http.StatusUnsupportedMediaType, res.StatusCode)
+	assert.Equal(suite.T(), "gzip, deflate, br, zstd", res.Header.Get(slide.HeaderAcceptEncoding))
+
+	res, _ = suite.request(DecompressConfig{}, EncodingGzip, []byte("not gzip"))
+	assert.Equal(suite.T(), http.StatusBadRequest, res.StatusCode)
+
+	truncated := encode(EncodingGzip, strings.Repeat("slide", 100))
+	res, _ = suite.request(DecompressConfig{}, EncodingGzip, truncated[:len(truncated)/2])
+	assert.Equal(suite.T(), http.StatusBadRequest, res.StatusCode)
+}
+
+func TestDecompress(t *testing.T) {
+	suite.Run(t, new(DecompressSuite))
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+
+	"github.com/go-slide/slide"
+)
+
+// ETagConfig configuration for ETag middleware
+type ETagConfig struct {
+	// Weak generate weak etags, use it when responses are semantically
+	// equal but not byte for byte, ex when compressed differently
+	Weak bool
+}
+
+// ETag middleware with strong etags
+func ETag() func(ctx *slide.Ctx) error {
+	return ETagWithConfig(ETagConfig{})
+}
+
+// ETagWithConfig generates ETag from hash of the response body for successful
+// GET and HEAD requests when the handler did not set one with ctx.SetETag,
+// and answers If-None-Match with 304
+func ETagWithConfig(config ETagConfig) func(ctx *slide.Ctx) error {
+	return func(ctx *slide.Ctx) error {
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+		response := &ctx.RequestCtx.Response
+		if !(ctx.RequestCtx.IsGet() || ctx.RequestCtx.IsHead()) || response.StatusCode() != http.StatusOK ||
+			response.IsBodyStream() || len(response.Header.Peek(slide.HeaderETag)) > 0 {
+			return nil
+		}
+		body := response.Body()
+		hash := sha1.Sum(body)
+		tag := strconv.FormatInt(int64(len(body)), 16) + "-" + base64.RawURLEncoding.EncodeToString(hash[:])
+		ctx.SetETag(tag, config.Weak)
+		ctx.CheckPreconditions()
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/go-slide/slide"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ETagSuite struct {
+	suite.Suite
+}
+
+func (suite *ETagSuite) request(mw func(ctx *slide.Ctx) error, method, path string, headers map[string]string) (*http.Response, string) {
+	app := slide.InitServer(&slide.Config{})
+	app.Use(mw)
+	app.Get("/", func(ctx *slide.Ctx) error {
+		return ctx.Send(http.StatusOK, "slide")
+	})
+	app.Post("/", func(ctx *slide.Ctx) error {
+		return ctx.Send(http.StatusOK, "slide")
+	})
+	app.Get("/versioned", func(ctx *slide.Ctx) error {
+		ctx.SetETag("v1", false)
+		return ctx.Send(http.StatusOK, "slide")
+	})
+	app.Get("/missing", func(ctx *slide.Ctx) error {
+		return ctx.Send(http.StatusNotFound, "missing")
+	})
+	r, err := http.NewRequest(method, "http://test"+path, nil)
+	if !assert.Nil(suite.T(), err) {
+		return &http.Response{}, ""
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return &http.Response{}, ""
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res, string(body)
+}
+
+func (suite *ETagSuite) TestNotModified() {
+	res, body := suite.request(ETag(), slide.GET, "/", nil)
+	tag := res.Header.Get(slide.HeaderETag)
+	assert.Equal(suite.T(), http.StatusOK, res.StatusCode)
+	assert.Equal(suite.T(), "slide", body)
+	assert.Regexp(suite.T(), `^"5-[A-Za-z0-9_-]+"$`, tag)
+
+	res, body = suite.request(ETag(), slide.GET, "/", map[string]string{slide.HeaderIfNoneMatch: tag})
+	assert.Equal(suite.T(), http.StatusNotModified, res.StatusCode)
+	assert.Equal(suite.T(), "", body)
+	assert.Equal(suite.T(), tag, res.Header.Get(slide.HeaderETag))
+
+	res, _ = suite.request(ETag(), slide.GET, "/", map[string]string{slide.HeaderIfNoneMatch: `"other", ` + tag})
+	assert.Equal(suite.T(), http.StatusNotModified, res.StatusCode, "any of the listed tags")
+
+	res, body = suite.request(ETag(), slide.GET, "/", map[string]string{slide.HeaderIfNoneMatch: `"other"`})
+	assert.Equal(suite.T(), http.StatusOK, res.StatusCode)
+	assert.Equal(suite.T(), "slide", body)
+
+	res, _ = suite.request(ETag(), slide.GET, "/", map[string]string{slide.HeaderIfMatch: `"other"`})
+	assert.Equal(suite.T(), http.StatusPreconditionFailed, res.StatusCode)
+}
+
+func (suite *ETagSuite) TestWeak() {
+	mw := ETagWithConfig(ETagConfig{Weak: true})
+	res, _ := suite.request(mw, slide.GET, "/", nil)
+	tag := res.Header.Get(slide.HeaderETag)
+	assert.Regexp(suite.T(), `^W/"`, tag)
+
+	// If-None-Match uses weak comparison
+	res, _ = suite.request(mw, slide.GET, "/", map[string]string{slide.HeaderIfNoneMatch: tag[2:]})
+	assert.Equal(suite.T(), http.StatusNotModified, res.StatusCode)
+}
+
+func (suite *ETagSuite) TestSkipped() {
+	res, _ := suite.request(ETag(), slide.GET, "/versioned", nil)
+	assert.Equal(suite.T(), `"v1"`, res.Header.Get(slide.HeaderETag), "etag of the handler is kept")
+	res, _ = suite.request(ETag(), slide.GET, "/versioned", map[string]string{slide.HeaderIfNoneMatch: `"v1"`})
+	assert.Equal(suite.T(), http.StatusNotModified, res.StatusCode)
+
+	res, _ = suite.request(ETag(), slide.POST, "/", nil)
+	assert.Empty(suite.T(), res.Header.Get(slide.HeaderETag))
+	res, _ = suite.request(ETag(), slide.GET, "/missing", nil)
+	assert.Empty(suite.T(), res.Header.Get(slide.HeaderETag))
+}
+
+func TestETag(t *testing.T) {
+	suite.Run(t, new(ETagSuite))
+}
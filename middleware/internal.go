@@ -1 +1,65 @@
 package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-slide/slide"
+)
+
+// extractor reads a value from the request
+type extractor func(ctx *slide.Ctx) string
+
+// parses lookup of form "<source>:<name>[,<source>:<name>]",
+// source can be header, query, cookie or form,
+// first non empty value wins
+func createExtractor(lookup, authScheme string) extractor {
+	var extractors []extractor
+	for _, part := range strings.Split(lookup, ",") {
+		pair := strings.SplitN(strings.TrimSpace(part), ":", 2)
+		if len(pair) != 2 {
+			panic(fmt.Errorf("invalid lookup %q", part))
+		}
+		name := pair[1]
+		switch pair[0] {
+		case "header":
+			extractors = append(extractors, func(ctx *slide.Ctx) string {
+				value := string(ctx.RequestCtx.Request.Header.Peek(name))
+				if authScheme == "" {
+					return value
+				}
+				prefix := authScheme + " "
+				if len(value) > len(prefix) && strings.EqualFold(value[:len(prefix)], prefix) {
+					return value[len(prefix):]
+				}
+				return ""
+			})
+		case "query":
+			extractors = append(extractors, func(ctx *slide.Ctx) string {
+				return string(ctx.RequestCtx.QueryArgs().Peek(name))
+			})
+		case "cookie":
+			extractors = append(extractors, func(ctx *slide.Ctx) string {
+				return string(ctx.RequestCtx.Request.Header.Cookie(name))
+			})
+		case "form":
+			extractors = append(extractors, func(ctx *slide.Ctx) string {
+				// the form is read within the body limit
+				if _, err := ctx.Body(); err != nil {
+					return ""
+				}
+				return string(ctx.RequestCtx.FormValue(name))
+			})
+		default:
+			panic(fmt.Errorf("invalid lookup source %q", pair[0]))
+		}
+	}
+	return func(ctx *slide.Ctx) string {
+		for _, e := range extractors {
+			if value := e(ctx); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+}
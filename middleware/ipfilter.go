@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-slide/slide"
+)
+
+// IPFilterConfig configuration for IPFilter middleware
+type IPFilterConfig struct {
+	// Allow CIDRs or addresses allowed, when empty every address
+	// not denied is allowed
+	Allow []string
+	// Deny CIDRs or addresses denied, checked before Allow
+	Deny []string
+	// File rules file added to Allow and Deny, one rule per line
+	// like "allow 10.0.0.0/8" or "deny 2001:db8::/32", lines starting
+	// with # are ignored. It is reloaded when modified, the first request
+	// after ReloadInterval checks its modification time
+	File string
+	// ReloadInterval how often File is checked for changes, defaults to 10 seconds
+	ReloadInterval time.Duration
+}
+
+var (
+	// DefaultIPFilterConfig default config for ip filter
+	DefaultIPFilterConfig = IPFilterConfig{
+		ReloadInterval: 10 * time.Second,
+	}
+
+	// ErrIPForbidden client address is not allowed
+	ErrIPForbidden = slide.NewError(http.StatusForbidden, "ip address not allowed")
+)
+
+// IPFilter rejects requests by client address from ctx.IP with ErrIPForbidden,
+// use it with app.Use, group.Use or as route middleware. Set
+// Config.TrustedProxies when the app runs behind proxies
+//
+//	admin := app.Group("/admin")
+//	admin.Use(middleware.IPFilter(middleware.IPFilterConfig{
+//		Allow: []string{"10.0.0.0/8", "fd00::/8"},
+//	}))
+func IPFilter(config IPFilterConfig) func(ctx *slide.Ctx) error {
+	if config.ReloadInterval == 0 {
+		config.ReloadInterval = DefaultIPFilterConfig.ReloadInterval
+	}
+	static, err := parseIPRules(config.Allow, config.Deny)
+	if err != nil {
+		panic(err)
+	}
+	filter := &ipFilter{static: static, file: config.File, reloadInterval: config.ReloadInterval}
+	if config.File != "" {
+		if err := filter.load(); err != nil {
+			panic(err)
+		}
+	}
+	return func(ctx *slide.Ctx) error {
+		ip := net.ParseIP(ctx.IP())
+		if ip == nil || !filter.allowed(ip) {
+			return ErrIPForbidden
+		}
+		return ctx.Next()
+	}
+}
+
+type ipRules struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// ipFilter static rules and rules of the file, the file is
+// checked for changes by a request every reloadInterval
+type ipFilter struct {
+	static         ipRules
+	file           string
+	reloadInterval time.Duration
+
+	reloading int32
+	mu        sync.RWMutex
+	fromFile  ipRules
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func (f *ipFilter) allowed(ip net.IP) bool {
+	if f.file != "" {
+		f.mu.RLock()
+		due := time.Since(f.checkedAt) >= f.reloadInterval
+		f.mu.RUnlock()
+		if due {
+			f.reload()
+		}
+	}
+	f.mu.RLock()
+	fromFile := f.fromFile
+	f.mu.RUnlock()
+	if containsIP(f.static.deny, ip) || containsIP(fromFile.deny, ip) {
+		return false
+	}
+	if len(f.static.allow) == 0 && len(fromFile.allow) == 0 {
+		return true
+	}
+	return containsIP(f.static.allow, ip) || containsIP(fromFile.allow, ip)
+}
+
+// reload loads rules if the file changed, skipped if another request is
+// reloading so only one of them waits, rules in use are kept when the
+// file is invalid
+func (f *ipFilter) reload() {
+	if !atomic.CompareAndSwapInt32(&f.reloading, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&f.reloading, 0)
+	info, err := os.Stat(f.file)
+	f.mu.RLock()
+	modified := err == nil && !info.ModTime().Equal(f.modTime)
+	f.mu.RUnlock()
+	if !modified || f.load() != nil {
+		f.mu.Lock()
+		f.checkedAt = time.Now()
+		f.mu.Unlock()
+	}
+}
+
+func (f *ipFilter) load() error {
+	info, err := os.Stat(f.file)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(f.file)
+	if err != nil {
+		return err
+	}
+	rules, err := parseIPRulesFile(data)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.fromFile = rules
+	f.modTime = info.ModTime()
+	f.checkedAt = time.Now()
+	f.mu.Unlock()
+	return nil
+}
+
+func parseIPRulesFile(data []byte) (ipRules, error) {
+	var allow, deny []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return ipRules{}, fmt.Errorf("ip filter: invalid rule on line %d", line)
+		}
+		switch strings.ToLower(fields[0]) {
+		case "allow":
+			allow = append(allow, fields[1])
+		case "deny":
+			deny = append(deny, fields[1])
+		default:
+			return ipRules{}, fmt.Errorf("ip filter: invalid rule on line %d", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ipRules{}, err
+	}
+	return parseIPRules(allow, deny)
+}
+
+func parseIPRules(allow, deny []string) (ipRules, error) {
+	var rules ipRules
+	var err error
+	if rules.allow, err = slide.ParseNetworks(allow); err != nil {
+		return ipRules{}, fmt.Errorf("ip filter: %v", err)
+	}
+	if rules.deny, err = slide.ParseNetworks(deny); err != nil {
+		return ipRules{}, fmt.Errorf("ip filter: %v", err)
+	}
+	return rules, nil
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-slide/slide"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type IPFilterSuite struct {
+	suite.Suite
+}
+
+// requests come from 0.0.0.0, ip is the client address behind it
+func (suite *IPFilterSuite) status(app *slide.Slide, ip string) int {
+	r, err := http.NewRequest(slide.GET, "http://test/", nil)
+	if !assert.Nil(suite.T(), err) {
+		return 0
+	}
+	r.Header.Set(slide.HeaderXForwardedFor, ip)
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return 0
+	}
+	return res.StatusCode
+}
+
+func (suite *IPFilterSuite) app(config IPFilterConfig) *slide.Slide {
+	app := slide.InitServer(&slide.Config{TrustedProxies: []string{"0.0.0.0"}})
+	app.Use(IPFilter(config))
+	app.Get("/", func(ctx *slide.Ctx) error {
+		return ctx.SendStatusCode(http.StatusOK)
+	})
+	return app
+}
+
+func (suite *IPFilterSuite) TestRules() {
+	tests := []struct {
+		config  IPFilterConfig
+		ip      string
+		allowed bool
+	}{
+		{IPFilterConfig{}, "1.1.1.1", true},
+		{IPFilterConfig{Allow: []string{"10.0.0.0/8"}}, "10.1.2.3", true},
+		{IPFilterConfig{Allow: []string{"10.0.0.0/8"}}, "1.1.1.1", false},
+		{IPFilterConfig{Deny: []string{"10.0.0.1"}}, "10.0.0.1", false},
+		{IPFilterConfig{Deny: []string{"10.0.0.1"}}, "10.0.0.2", true},
+		// deny wins over allow
+		{IPFilterConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.0/24"}}, "10.0.0.5", false},
+		{IPFilterConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.0/24"}}, "10.0.1.5", true},
+		{IPFilterConfig{Allow: []string{"2001:db8::/32"}}, "2001:db8::1", true},
+		{IPFilterConfig{Allow: []string{"::1"}}, "::2", false},
+	}
+	for _, test := range tests {
+		status := http.StatusForbidden
+		if test.allowed {
+			status = http.StatusOK
+		}
+		assert.Equal(suite.T(), status, suite.status(suite.app(test.config), test.ip), test.ip)
+	}
+}
+
+func (suite *IPFilterSuite) TestSpoofedForwarded() {
+	app := suite.app(IPFilterConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"6.6.6.6"}})
+	for _, headers := range []map[string]string{
+		// allowed address claimed through a header the proxy does not set
+		{slide.HeaderXForwardedFor: "6.6.6.6", slide.HeaderForwarded: "for=10.1.2.3"},
+		{slide.HeaderXForwardedFor: "6.6.6.6", slide.HeaderXRealIP: "10.1.2.3"},
+		// addresses before the client can be spoofed
+		{slide.HeaderXForwardedFor: "10.1.2.3, 6.6.6.6"},
+	} {
+		r, err := http.NewRequest(slide.GET, "http://test/", nil)
+		if !assert.Nil(suite.T(), err) {
+			continue
+		}
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		res, err := testServer(r, app)
+		if assert.Nil(suite.T(), err) {
+			assert.Equal(suite.T(), http.StatusForbidden, res.StatusCode, headers)
+		}
+	}
+}
+
+func (suite *IPFilterSuite) TestInvalidRules() {
+	assert.Panics(suite.T(), func() {
+		IPFilter(IPFilterConfig{Allow: []string{"10.0.0.300"}})
+	})
+	assert.Panics(suite.T(), func() {
+		IPFilter(IPFilterConfig{Deny: []string{"10.0.0.0/33"}})
+	})
+	assert.Panics(suite.T(), func() {
+		IPFilter(IPFilterConfig{File: filepath.Join(os.TempDir(), "missing-ip-rules")})
+	})
+}
+
+func (suite *IPFilterSuite) TestFileReload() {
+	dir, err := ioutil.TempDir("", "ipfilter")
+	if !assert.Nil(suite.T(), err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "rules")
+	modTime := time.Now().Add(-time.Hour)
+	write := func(rules string) {
+		assert.Nil(suite.T(), ioutil.WriteFile(file, []byte(rules), 0600))
+		// mod time has to change even on file systems with coarse timestamps
+		modTime = modTime.Add(time.Second)
+		assert.Nil(suite.T(), os.Chtimes(file, modTime, modTime))
+	}
+	write("# office\nallow 10.0.0.0/8\ndeny 10.0.0.1\n")
+	app := suite.app(IPFilterConfig{File: file, ReloadInterval: time.Millisecond})
+	assert.Equal(suite.T(), http.StatusOK, suite.status(app, "10.0.0.2"))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.status(app, "10.0.0.1"))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.status(app, "1.1.1.1"))
+
+	write("allow 1.1.1.1\n")
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(suite.T(), http.StatusOK, suite.status(app, "1.1.1.1"))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.status(app, "10.0.0.2"))
+
+	// rules in use are kept when the file is invalid
+	write("allow everyone\n")
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(suite.T(), http.StatusOK, suite.status(app, "1.1.1.1"))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.status(app, "10.0.0.2"))
+}
+
+func TestIPFilter(t *testing.T) {
+	suite.Run(t, new(IPFilterSuite))
+}
@@ -0,0 +1,253 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const jwksFetchTimeout = 10 * time.Second
+
+// json web key, reference https://tools.ietf.org/html/rfc7517
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// verification key parsed from a jwk
+type jwtKey struct {
+	alg string
+	key interface{}
+}
+
+// jwks keys loaded from a file or URL, cached in memory and
+// refreshed every refreshInterval to pick up rotated keys,
+// an unknown kid forces a refresh at most once per minRefreshInterval.
+// Failed loads count as attempts too, so an unreachable URL is not
+// fetched by every request
+type jwks struct {
+	file               string
+	url                string
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+
+	mu          sync.RWMutex
+	keys        map[string]jwtKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	loadErr     error
+	loading     *jwksLoad
+}
+
+// jwksLoad load in flight, shared by requests waiting for it
+type jwksLoad struct {
+	done chan struct{}
+	err  error
+}
+
+func (s *jwks) key(kid string) (jwtKey, error) {
+	s.mu.RLock()
+	k, ok := s.keys[kid]
+	loaded := s.keys != nil
+	fetchedAt, attemptedAt := s.fetchedAt, s.attemptedAt
+	loadErr := s.loadErr
+	loading := s.loading != nil
+	s.mu.RUnlock()
+	canLoad := !loading && time.Since(attemptedAt) >= s.minRefreshInterval
+	if ok {
+		if canLoad && time.Since(fetchedAt) >= s.refreshInterval {
+			// keep serving cached key while the set is refreshed
+			go func() { _ = s.reload() }()
+		}
+		return k, nil
+	}
+	if !loading && !canLoad {
+		if !loaded && loadErr != nil {
+			return jwtKey{}, loadErr
+		}
+		return jwtKey{}, fmt.Errorf("unknown kid %q", kid)
+	}
+	if err := s.reload(); err != nil {
+		return jwtKey{}, err
+	}
+	s.mu.RLock()
+	k, ok = s.keys[kid]
+	s.mu.RUnlock()
+	if !ok {
+		return jwtKey{}, fmt.Errorf("unknown kid %q", kid)
+	}
+	return k, nil
+}
+
+// reload loads keys, or waits for the load in flight and
+// returns its result
+func (s *jwks) reload() error {
+	s.mu.Lock()
+	if l := s.loading; l != nil {
+		s.mu.Unlock()
+		<-l.done
+		return l.err
+	}
+	l := &jwksLoad{done: make(chan struct{})}
+	s.loading = l
+	s.attemptedAt = time.Now()
+	s.mu.Unlock()
+	l.err = s.load()
+	s.mu.Lock()
+	s.loading = nil
+	s.loadErr = l.err
+	s.mu.Unlock()
+	close(l.done)
+	return l.err
+}
+
+func (s *jwks) load() error {
+	data, err := s.fetch()
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *jwks) fetch() ([]byte, error) {
+	if s.file != "" {
+		return ioutil.ReadFile(s.file)
+	}
+	status, body, err := fasthttp.GetTimeout(nil, s.url, jwksFetchTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("jwks: unexpected status %d from %s", status, s.url)
+	}
+	return body, nil
+}
+
+func parseJWKS(data []byte) (map[string]jwtKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := map[string]jwtKey{}
+	var skipped error
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// sets often publish keys of other algorithms next to the ones in use,
+		// like P-384 curves, which are skipped
+		key, err := k.parse()
+		if err != nil {
+			skipped = fmt.Errorf("jwks: key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		if skipped != nil {
+			return nil, skipped
+		}
+		return nil, errors.New("jwks: no signing keys")
+	}
+	return keys, nil
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func (k jsonWebKey) parse() (jwtKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return jwtKey{}, err
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return jwtKey{}, err
+		}
+		pub := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		return jwtKey{alg: algOrDefault(k.Alg, AlgorithmRS256), key: pub}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return jwtKey{}, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return jwtKey{}, err
+		}
+		y, err := decodeSegment(k.Y)
+		if err != nil {
+			return jwtKey{}, err
+		}
+		pub := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return jwtKey{}, errors.New("point is not on curve")
+		}
+		return jwtKey{alg: algOrDefault(k.Alg, AlgorithmES256), key: pub}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return jwtKey{}, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return jwtKey{}, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return jwtKey{}, errors.New("invalid ed25519 key size")
+		}
+		return jwtKey{alg: algOrDefault(k.Alg, AlgorithmEdDSA), key: ed25519.PublicKey(x)}, nil
+	case "oct":
+		secret, err := decodeSegment(k.K)
+		if err != nil {
+			return jwtKey{}, err
+		}
+		return jwtKey{alg: algOrDefault(k.Alg, AlgorithmHS256), key: secret}, nil
+	}
+	return jwtKey{}, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func algOrDefault(alg, def string) string {
+	if alg == "" {
+		return def
+	}
+	return alg
+}
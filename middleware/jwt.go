@@ -0,0 +1,298 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-slide/slide"
+)
+
+// supported signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// JWTConfig configuration for JWT middleware
+type JWTConfig struct {
+	// SigningKey key used to verify tokens when no JWKS is configured,
+	// []byte for HS256, *rsa.PublicKey for RS256, *ecdsa.PublicKey for ES256
+	// and ed25519.PublicKey for EdDSA
+	SigningKey interface{}
+	// SigningMethod algorithm of SigningKey, defaults to HS256
+	SigningMethod string
+	// JWKSFile path of a local JSON Web Key Set
+	JWKSFile string
+	// JWKSURL URL of a remote JSON Web Key Set
+	JWKSURL string
+	// JWKSRefreshInterval how long keys are cached before reloading, defaults to 1 hour
+	JWKSRefreshInterval time.Duration
+	// JWKSMinRefreshInterval minimum wait between reloads triggered by unknown key ids,
+	// defaults to 5 minutes
+	JWKSMinRefreshInterval time.Duration
+	// Issuer expected iss claim, not checked if empty
+	Issuer string
+	// Audience accepted aud claims, not checked if empty
+	Audience []string
+	// Leeway allowed clock skew for exp, nbf and iat
+	Leeway time.Duration
+	// TokenLookup where to find the token, "<source>:<name>" separated by comma,
+	// source can be header, cookie, query or form. defaults to "header:Authorization"
+	TokenLookup string
+	// AuthScheme scheme prefix for header lookups, defaults to "Bearer"
+	AuthScheme string
+	// ContextKey key for claims on Ctx, defaults to "user"
+	ContextKey string
+}
+
+// JWTClaims claims of a verified token, available to handlers with
+//
+//	claims := ctx.Get("user").(middleware.JWTClaims)
+type JWTClaims map[string]interface{}
+
+// Subject returns sub claim
+func (c JWTClaims) Subject() string {
+	s, _ := c["sub"].(string)
+	return s
+}
+
+var (
+	// DefaultJWTConfig default config for jwt
+	DefaultJWTConfig = JWTConfig{
+		SigningMethod:          AlgorithmHS256,
+		JWKSRefreshInterval:    time.Hour,
+		JWKSMinRefreshInterval: 5 * time.Minute,
+		TokenLookup:            "header:" + slide.HeaderAuthorization,
+		AuthScheme:             "Bearer",
+		ContextKey:             "user",
+	}
+
+	// ErrJWTMissing token not found in request
+	ErrJWTMissing = slide.NewError(http.StatusUnauthorized, "missing or malformed jwt")
+	// ErrJWTInvalid token failed verification or claim validation
+	ErrJWTInvalid = slide.NewError(http.StatusUnauthorized, "invalid or expired jwt")
+)
+
+// JWT authentication middleware, verifies bearer tokens and stores
+// claims on Ctx, failures are returned as errors so they reach HandleErrors
+// Reference https://tools.ietf.org/html/rfc7519
+func JWT(config JWTConfig) func(ctx *slide.Ctx) error {
+	if config.SigningMethod == "" {
+		config.SigningMethod = DefaultJWTConfig.SigningMethod
+	}
+	if config.JWKSRefreshInterval == 0 {
+		config.JWKSRefreshInterval = DefaultJWTConfig.JWKSRefreshInterval
+	}
+	if config.JWKSMinRefreshInterval == 0 {
+		config.JWKSMinRefreshInterval = DefaultJWTConfig.JWKSMinRefreshInterval
+	}
+	if config.TokenLookup == "" {
+		config.TokenLookup = DefaultJWTConfig.TokenLookup
+	}
+	if config.AuthScheme == "" {
+		config.AuthScheme = DefaultJWTConfig.AuthScheme
+	}
+	if config.ContextKey == "" {
+		config.ContextKey = DefaultJWTConfig.ContextKey
+	}
+	var keySet *jwks
+	if config.JWKSFile != "" || config.JWKSURL != "" {
+		keySet = &jwks{
+			file:               config.JWKSFile,
+			url:                config.JWKSURL,
+			refreshInterval:    config.JWKSRefreshInterval,
+			minRefreshInterval: config.JWKSMinRefreshInterval,
+		}
+	} else if config.SigningKey == nil {
+		panic(errors.New("jwt middleware requires SigningKey or JWKS"))
+	}
+	extract := createExtractor(config.TokenLookup, config.AuthScheme)
+	return func(ctx *slide.Ctx) error {
+		token := extract(ctx)
+		if token == "" {
+			return ErrJWTMissing
+		}
+		claims, err := parseJWT(token, &config, keySet)
+		if err != nil {
+			return ErrJWTInvalid.WithInternal(err)
+		}
+		ctx.Set(config.ContextKey, claims)
+		return ctx.Next()
+	}
+}
+
+func parseJWT(token string, config *JWTConfig, keySet *jwks) (JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token should have three segments")
+	}
+	headerBytes, err := decodeSegment(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, err
+	}
+	key := jwtKey{alg: config.SigningMethod, key: config.SigningKey}
+	if keySet != nil {
+		if key, err = keySet.key(header.Kid); err != nil {
+			return nil, err
+		}
+	}
+	// never trust alg from token without matching it against the key
+	if header.Alg != key.alg {
+		return nil, fmt.Errorf("unexpected signing method %q", header.Alg)
+	}
+	signature, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	claims := JWTClaims{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims); err != nil {
+		return nil, err
+	}
+	if err := validateClaims(claims, config, time.Now()); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func verifySignature(key jwtKey, signed, signature []byte) error {
+	digest := sha256.Sum256(signed)
+	switch key.alg {
+	case AlgorithmHS256:
+		secret, ok := key.key.([]byte)
+		if !ok {
+			return errors.New("HS256 requires []byte key")
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("signature is invalid")
+		}
+		return nil
+	case AlgorithmRS256:
+		pub, ok := key.key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("RS256 requires *rsa.PublicKey")
+		}
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature)
+	case AlgorithmES256:
+		pub, ok := key.key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("ES256 requires *ecdsa.PublicKey")
+		}
+		if len(signature) != 64 {
+			return errors.New("signature is invalid")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return errors.New("signature is invalid")
+		}
+		return nil
+	case AlgorithmEdDSA:
+		pub, ok := key.key.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("EdDSA requires ed25519.PublicKey")
+		}
+		if !ed25519.Verify(pub, signed, signature) {
+			return errors.New("signature is invalid")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported signing method %q", key.alg)
+}
+
+func validateClaims(claims JWTClaims, config *JWTConfig, now time.Time) error {
+	if exp, ok, err := numericDate(claims, "exp"); err != nil {
+		return err
+	} else if ok && !now.Before(exp.Add(config.Leeway)) {
+		return errors.New("token is expired")
+	}
+	if nbf, ok, err := numericDate(claims, "nbf"); err != nil {
+		return err
+	} else if ok && now.Add(config.Leeway).Before(nbf) {
+		return errors.New("token is not valid yet")
+	}
+	if iat, ok, err := numericDate(claims, "iat"); err != nil {
+		return err
+	} else if ok && now.Add(config.Leeway).Before(iat) {
+		return errors.New("token used before issued")
+	}
+	if config.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != config.Issuer {
+			return errors.New("invalid issuer")
+		}
+	}
+	if len(config.Audience) > 0 && !audienceMatches(claims["aud"], config.Audience) {
+		return errors.New("invalid audience")
+	}
+	return nil
+}
+
+func numericDate(claims JWTClaims, name string) (time.Time, bool, error) {
+	v, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("invalid %s claim", name)
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s claim", name)
+	}
+	return time.Unix(0, int64(f*float64(time.Second))), true, nil
+}
+
+// aud can be a single string or an array of strings
+func audienceMatches(aud interface{}, accepted []string) bool {
+	var values []string
+	switch a := aud.(type) {
+	case string:
+		values = []string{a}
+	case []interface{}:
+		for _, v := range a {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	for _, v := range values {
+		for _, expected := range accepted {
+			if v == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
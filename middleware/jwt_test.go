@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-slide/slide"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type JWTSuite struct {
+	suite.Suite
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+func (suite *JWTSuite) SetupSuite() {
+	var err error
+	suite.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(suite.T(), err)
+	suite.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(suite.T(), err)
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// signs claims with key, []byte for HS256, *rsa.PrivateKey for RS256
+// and *ecdsa.PrivateKey for ES256
+func (suite *JWTSuite) sign(alg, kid string, key interface{}, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signed := encodeSegment(header) + "." + encodeSegment(payload)
+	digest := sha256.Sum256([]byte(signed))
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		assert.Nil(suite.T(), err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		assert.Nil(suite.T(), err)
+		// r and s padded to 32 bytes each
+		signature = make([]byte, 64)
+		rb, sb := r.Bytes(), s.Bytes()
+		copy(signature[32-len(rb):32], rb)
+		copy(signature[64-len(sb):], sb)
+	}
+	return signed + "." + encodeSegment(signature)
+}
+
+func (suite *JWTSuite) request(mw func(ctx *slide.Ctx) error, token string) (int, string) {
+	app := slide.InitServer(&slide.Config{})
+	app.Get("/", func(ctx *slide.Ctx) error {
+		return ctx.Send(http.StatusOK, ctx.Get("user").(JWTClaims).Subject())
+	}, mw)
+	r, err := http.NewRequest(slide.GET, "http://test/", nil)
+	if !assert.Nil(suite.T(), err) {
+		return 0, ""
+	}
+	if token != "" {
+		r.Header.Set(slide.HeaderAuthorization, "Bearer "+token)
+	}
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return 0, ""
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res.StatusCode, string(body)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{"sub": "slide", "aud": "api", "exp": time.Now().Add(time.Minute).Unix()}
+}
+
+func (suite *JWTSuite) TestSigningMethods() {
+	secret := []byte("secret")
+	tests := []struct {
+		config JWTConfig
+		token  string
+	}{
+		{JWTConfig{SigningKey: secret}, suite.sign(AlgorithmHS256, "", secret, validClaims())},
+		{JWTConfig{SigningKey: &suite.rsaKey.PublicKey, SigningMethod: AlgorithmRS256}, suite.sign(AlgorithmRS256, "", suite.rsaKey, validClaims())},
+		{JWTConfig{SigningKey: &suite.ecKey.PublicKey, SigningMethod: AlgorithmES256}, suite.sign(AlgorithmES256, "", suite.ecKey, validClaims())},
+	}
+	for _, test := range tests {
+		test.config.Audience = []string{"api"}
+		status, body := suite.request(JWT(test.config), test.token)
+		assert.Equal(suite.T(), http.StatusOK, status, test.config.SigningMethod)
+		assert.Equal(suite.T(), "slide", body)
+		status, _ = suite.request(JWT(test.config), test.token+"x")
+		assert.Equal(suite.T(), http.StatusUnauthorized, status, test.config.SigningMethod)
+	}
+	// alg of the token has to match the key
+	status, _ := suite.request(JWT(JWTConfig{SigningKey: &suite.rsaKey.PublicKey, SigningMethod: AlgorithmRS256}), suite.sign(AlgorithmHS256, "", secret, validClaims()))
+	assert.Equal(suite.T(), http.StatusUnauthorized, status)
+}
+
+func (suite *JWTSuite) TestClaims() {
+	secret := []byte("secret")
+	mw := JWT(JWTConfig{SigningKey: secret, Issuer: "slide", Leeway: time.Minute})
+	claims := map[string]interface{}{"sub": "slide", "iss": "slide", "exp": time.Now().Add(-30 * time.Second).Unix()}
+	status, _ := suite.request(mw, suite.sign(AlgorithmHS256, "", secret, claims))
+	assert.Equal(suite.T(), http.StatusOK, status, "expired within leeway")
+
+	claims["exp"] = time.Now().Add(-2 * time.Minute).Unix()
+	status, _ = suite.request(mw, suite.sign(AlgorithmHS256, "", secret, claims))
+	assert.Equal(suite.T(), http.StatusUnauthorized, status, "expired")
+
+	claims["exp"] = time.Now().Add(time.Minute).Unix()
+	claims["nbf"] = time.Now().Add(2 * time.Minute).Unix()
+	status, _ = suite.request(mw, suite.sign(AlgorithmHS256, "", secret, claims))
+	assert.Equal(suite.T(), http.StatusUnauthorized, status, "not valid yet")
+
+	delete(claims, "nbf")
+	claims["iss"] = "other"
+	status, _ = suite.request(mw, suite.sign(AlgorithmHS256, "", secret, claims))
+	assert.Equal(suite.T(), http.StatusUnauthorized, status, "issuer")
+
+	status, _ = suite.request(mw, "")
+	assert.Equal(suite.T(), http.StatusUnauthorized, status, "missing")
+}
+
+func (suite *JWTSuite) jwk(kid string) map[string]string {
+	pub := suite.ecKey.PublicKey
+	return map[string]string{
+		"kty": "EC",
+		"crv": "P-256",
+		"kid": kid,
+		"x":   encodeSegment(pub.X.Bytes()),
+		"y":   encodeSegment(pub.Y.Bytes()),
+	}
+}
+
+func (suite *JWTSuite) TestJWKS() {
+	rsaJWK := map[string]string{
+		"kty": "RSA",
+		"kid": "rsa",
+		"n":   encodeSegment(suite.rsaKey.N.Bytes()),
+		"e":   encodeSegment(big.NewInt(int64(suite.rsaKey.E)).Bytes()),
+	}
+	set, _ := json.Marshal(map[string]interface{}{"keys": []interface{}{
+		// unsupported keys are skipped
+		map[string]string{"kty": "EC", "crv": "P-384", "kid": "p384", "x": "AA", "y": "AA"},
+		map[string]string{"kty": "unknown", "kid": "unknown"},
+		suite.jwk("ec"),
+		rsaJWK,
+	}})
+	file, err := ioutil.TempFile("", "jwks")
+	if !assert.Nil(suite.T(), err) {
+		return
+	}
+	defer file.Close()
+	_, err = file.Write(set)
+	assert.Nil(suite.T(), err)
+
+	mw := JWT(JWTConfig{JWKSFile: file.Name()})
+	status, _ := suite.request(mw, suite.sign(AlgorithmES256, "ec", suite.ecKey, validClaims()))
+	assert.Equal(suite.T(), http.StatusOK, status)
+	status, _ = suite.request(mw, suite.sign(AlgorithmRS256, "rsa", suite.rsaKey, validClaims()))
+	assert.Equal(suite.T(), http.StatusOK, status)
+	// key of another kid
+	status, _ = suite.request(mw, suite.sign(AlgorithmRS256, "ec", suite.rsaKey, validClaims()))
+	assert.Equal(suite.T(), http.StatusUnauthorized, status)
+	status, _ = suite.request(mw, suite.sign(AlgorithmES256, "p384", suite.ecKey, validClaims()))
+	assert.Equal(suite.T(), http.StatusUnauthorized, status)
+
+	_, err = parseJWKS([]byte(`{"keys":[{"kty":"EC","crv":"P-521","kid":"p521"}]}`))
+	assert.NotNil(suite.T(), err, "no usable key")
+}
+
+func (suite *JWTSuite) TestJWKSRefresh() {
+	kids := atomic.Value{}
+	kids.Store([]string{"old"})
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		var keys []interface{}
+		for _, kid := range kids.Load().([]string) {
+			keys = append(keys, suite.jwk(kid))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer server.Close()
+
+	mw := JWT(JWTConfig{JWKSURL: server.URL})
+	status, _ := suite.request(mw, suite.sign(AlgorithmES256, "old", suite.ecKey, validClaims()))
+	assert.Equal(suite.T(), http.StatusOK, status)
+	status, _ = suite.request(mw, suite.sign(AlgorithmES256, "old", suite.ecKey, validClaims()))
+	assert.Equal(suite.T(), http.StatusOK, status)
+	assert.Equal(suite.T(), int32(1), atomic.LoadInt32(&fetches), "keys are cached")
+
+	// rotated keys are picked up by unknown kids, at most once per
+	// JWKSMinRefreshInterval
+	kids.Store([]string{"old", "new"})
+	status, _ = suite.request(mw, suite.sign(AlgorithmES256, "new", suite.ecKey, validClaims()))
+	assert.Equal(suite.T(), http.StatusUnauthorized, status)
+	assert.Equal(suite.T(), int32(1), atomic.LoadInt32(&fetches))
+
+	mw = JWT(JWTConfig{JWKSURL: server.URL, JWKSMinRefreshInterval: time.Nanosecond})
+	kids.Store([]string{"old"})
+	status, _ = suite.request(mw, suite.sign(AlgorithmES256, "old", suite.ecKey, validClaims()))
+	assert.Equal(suite.T(), http.StatusOK, status)
+	kids.Store([]string{"old", "new"})
+	status, _ = suite.request(mw, suite.sign(AlgorithmES256, "new", suite.ecKey, validClaims()))
+	assert.Equal(suite.T(), http.StatusOK, status)
+	assert.Equal(suite.T(), int32(3), atomic.LoadInt32(&fetches))
+}
+
+func (suite *JWTSuite) TestJWKSUnreachable() {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		// keeps the load in flight while the other requests arrive
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	mw := JWT(JWTConfig{JWKSURL: server.URL})
+	token := suite.sign(AlgorithmES256, "old", suite.ecKey, validClaims())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status, _ := suite.request(mw, token)
+			assert.Equal(suite.T(), http.StatusUnauthorized, status)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(suite.T(), int32(1), atomic.LoadInt32(&fetches), "waiting requests share the load")
+
+	// failed loads are attempts, retried after JWKSMinRefreshInterval
+	status, _ := suite.request(mw, token)
+	assert.Equal(suite.T(), http.StatusUnauthorized, status)
+	assert.Equal(suite.T(), int32(1), atomic.LoadInt32(&fetches))
+}
+
+func TestJWT(t *testing.T) {
+	suite.Run(t, new(JWTSuite))
+}
@@ -0,0 +1,387 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-slide/slide"
+	"github.com/valyala/fasthttp"
+)
+
+// Upstream server requests are forwarded to, Proxy works on copies
+// so an Upstream can be shared by several proxies
+type Upstream struct {
+	// URL of the server, ex http://10.0.0.1:8080
+	URL string
+	// Weight share of requests with the weighted balancer, defaults to 1
+	Weight int
+
+	client  *fasthttp.HostClient
+	host    string
+	active  int64
+	healthy int32
+}
+
+// Active returns number of requests being forwarded to upstream
+func (u *Upstream) Active() int64 {
+	return atomic.LoadInt64(&u.active)
+}
+
+// Healthy returns false if last health check of upstream failed
+func (u *Upstream) Healthy() bool {
+	return atomic.LoadInt32(&u.healthy) == 1
+}
+
+// Balancer picks upstream for a request from the healthy upstreams
+// not yet tried for it, upstreams is never empty
+type Balancer interface {
+	Next(ctx *slide.Ctx, upstreams []*Upstream) *Upstream
+}
+
+// HealthCheckConfig active health checks of upstreams
+type HealthCheckConfig struct {
+	// Path requested on each upstream with GET, empty disables health checks
+	Path string
+	// Interval between checks, defaults to 10 seconds
+	Interval time.Duration
+	// Timeout of a check, defaults to 2 seconds
+	Timeout time.Duration
+	// Stop closing it stops health checks, ex when the server shuts down,
+	// checks run as long as the process otherwise
+	Stop <-chan struct{}
+}
+
+// ProxyConfig configuration for Proxy middleware
+type ProxyConfig struct {
+	Upstreams []*Upstream
+	// Balancer defaults to RoundRobinBalancer
+	Balancer    Balancer
+	HealthCheck HealthCheckConfig
+	// Rewrite path rewrite rules, "*" matches anything and is referenced
+	// with $1, $2 in the replacement, ex "/api/*": "/v1/$1". Replacements
+	// can carry a query which is added to the request query
+	Rewrite map[string]string
+	// PreserveHost forwards Host of the request instead of host of the upstream
+	PreserveHost bool
+	// SetHeaders headers set on forwarded requests
+	SetHeaders map[string]string
+	// RemoveHeaders headers removed from forwarded requests
+	RemoveHeaders []string
+	// Retries number of other upstreams tried when forwarding fails,
+	// only for idempotent methods
+	Retries int
+	// Timeout of a forwarded request, defaults to 30 seconds
+	Timeout time.Duration
+	// ModifyRequest called before request is forwarded
+	ModifyRequest func(ctx *slide.Ctx, req *fasthttp.Request) error
+	// ModifyResponse called with upstream response before it is sent
+	ModifyResponse func(ctx *slide.Ctx, res *fasthttp.Response) error
+}
+
+var (
+	// DefaultProxyConfig default config for proxy
+	DefaultProxyConfig = ProxyConfig{
+		Timeout: 30 * time.Second,
+		HealthCheck: HealthCheckConfig{
+			Interval: 10 * time.Second,
+			Timeout:  2 * time.Second,
+		},
+	}
+
+	// ErrNoHealthyUpstream all upstreams failed their health checks
+	ErrNoHealthyUpstream = slide.NewError(http.StatusServiceUnavailable, "no healthy upstream")
+)
+
+// hop-by-hop headers are not forwarded
+// Reference https://tools.ietf.org/html/rfc7230#section-6.1
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Proxy-Connection", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// Proxy forwards requests to upstreams and sends back their responses,
+// use it with group.Use to front a service under a path
+//
+//	api := app.Group("/api")
+//	api.Use(middleware.Proxy(middleware.ProxyConfig{
+//		Upstreams: []*middleware.Upstream{{URL: "http://10.0.0.1:8080"}, {URL: "http://10.0.0.2:8080"}},
+//		Rewrite:   map[string]string{"/api/*": "/$1"},
+//	}))
+//
+// X-Forwarded-For, X-Forwarded-Proto, X-Forwarded-Host and X-Real-IP
+// are set on forwarded requests. Failed requests return slide.ErrBadGateway,
+// or slide.ErrGatewayTimeout when the upstream did not respond in time
+func Proxy(config ProxyConfig) func(ctx *slide.Ctx) error {
+	if len(config.Upstreams) == 0 {
+		panic("proxy: no upstreams")
+	}
+	if config.Balancer == nil {
+		config.Balancer = RoundRobinBalancer()
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultProxyConfig.Timeout
+	}
+	if config.HealthCheck.Interval == 0 {
+		config.HealthCheck.Interval = DefaultProxyConfig.HealthCheck.Interval
+	}
+	if config.HealthCheck.Timeout == 0 {
+		config.HealthCheck.Timeout = DefaultProxyConfig.HealthCheck.Timeout
+	}
+	upstreams := make([]*Upstream, len(config.Upstreams))
+	for i, src := range config.Upstreams {
+		parsed, err := url.Parse(src.URL)
+		if err != nil || parsed.Host == "" {
+			panic("proxy: invalid upstream url " + src.URL)
+		}
+		u := &Upstream{URL: src.URL, Weight: src.Weight, host: parsed.Host, healthy: 1}
+		if u.Weight <= 0 {
+			u.Weight = 1
+		}
+		u.client = &fasthttp.HostClient{
+			Addr:                   parsed.Host,
+			IsTLS:                  parsed.Scheme == "https",
+			DisablePathNormalizing: true,
+		}
+		upstreams[i] = u
+	}
+	config.Upstreams = upstreams
+	if config.HealthCheck.Path != "" {
+		go healthCheck(config.Upstreams, config.HealthCheck)
+	}
+	rewrites := compileRewriteRules(config.Rewrite)
+	return func(ctx *slide.Ctx) error {
+		// streamed bodies are read before they are copied
+		if _, err := ctx.Body(); err != nil {
+			return err
+		}
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+		res := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseResponse(res)
+		ctx.RequestCtx.Request.CopyTo(req)
+		if path, ok := rewritePath(rewrites, string(ctx.RequestCtx.Path())); ok {
+			setRewrittenURI(req.URI(), path)
+		}
+		for _, h := range hopHeaders {
+			req.Header.Del(h)
+		}
+		setForwardedHeaders(ctx, req)
+		for _, h := range config.RemoveHeaders {
+			req.Header.Del(h)
+		}
+		for k, v := range config.SetHeaders {
+			req.Header.Set(k, v)
+		}
+		if config.ModifyRequest != nil {
+			if err := config.ModifyRequest(ctx, req); err != nil {
+				return err
+			}
+		}
+		attempts := 1
+		if isIdempotent(ctx) {
+			attempts += config.Retries
+		}
+		var tried []*Upstream
+		var err error
+		for i := 0; i < attempts; i++ {
+			upstream := pickUpstream(ctx, config.Balancer, config.Upstreams, tried)
+			if upstream == nil {
+				if len(tried) == 0 {
+					return ErrNoHealthyUpstream
+				}
+				break
+			}
+			tried = append(tried, upstream)
+			if !config.PreserveHost {
+				req.SetHost(upstream.host)
+			}
+			res.Reset()
+			atomic.AddInt64(&upstream.active, 1)
+			err = upstream.client.DoTimeout(req, res, config.Timeout)
+			atomic.AddInt64(&upstream.active, -1)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			if errors.Is(err, fasthttp.ErrTimeout) {
+				return slide.ErrGatewayTimeout.WithInternal(err)
+			}
+			return slide.ErrBadGateway.WithInternal(err)
+		}
+		for _, h := range hopHeaders {
+			res.Header.Del(h)
+		}
+		if config.ModifyResponse != nil {
+			if err := config.ModifyResponse(ctx, res); err != nil {
+				return err
+			}
+		}
+		copyResponse(ctx, res)
+		return nil
+	}
+}
+
+// copies upstream response, keeping headers already set by middlewares,
+// single valued headers of upstream replace them and Vary is merged
+func copyResponse(ctx *slide.Ctx, src *fasthttp.Response) {
+	dst := &ctx.RequestCtx.Response
+	dst.SetStatusCode(src.StatusCode())
+	src.Header.VisitAll(func(key, value []byte) {
+		switch string(key) {
+		case slide.HeaderContentLength, "Connection":
+		case slide.ContentType, "Server", "Date":
+			dst.Header.SetBytesKV(key, value)
+		case slide.HeaderVary:
+			for _, v := range strings.Split(string(value), ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					ctx.Vary(v)
+				}
+			}
+		default:
+			dst.Header.AddBytesKV(key, value)
+		}
+	})
+	dst.SetBody(src.Body())
+}
+
+func setForwardedHeaders(ctx *slide.Ctx, req *fasthttp.Request) {
+	ip := ctx.RequestCtx.RemoteIP().String()
+	if prior := string(req.Header.Peek(slide.HeaderXForwardedFor)); prior != "" {
+		req.Header.Set(slide.HeaderXForwardedFor, prior+", "+ip)
+	} else {
+		req.Header.Set(slide.HeaderXForwardedFor, ip)
+	}
+	req.Header.Set(slide.HeaderXForwardedProto, ctx.Scheme())
+	req.Header.Set(slide.HeaderXForwardedHost, ctx.Host())
+	req.Header.Set(slide.HeaderXRealIP, ctx.IP())
+}
+
+func isIdempotent(ctx *slide.Ctx) bool {
+	switch string(ctx.RequestCtx.Method()) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// returns nil when every healthy upstream was tried
+func pickUpstream(ctx *slide.Ctx, balancer Balancer, upstreams, tried []*Upstream) *Upstream {
+	candidates := make([]*Upstream, 0, len(upstreams))
+	for _, u := range upstreams {
+		if u.Healthy() && !containsUpstream(tried, u) {
+			candidates = append(candidates, u)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return balancer.Next(ctx, candidates)
+}
+
+func containsUpstream(upstreams []*Upstream, u *Upstream) bool {
+	for _, v := range upstreams {
+		if v == u {
+			return true
+		}
+	}
+	return false
+}
+
+func healthCheck(upstreams []*Upstream, config HealthCheckConfig) {
+	check := func(u *Upstream) {
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+		res := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseResponse(res)
+		req.SetRequestURI(strings.TrimSuffix(u.URL, "/") + config.Path)
+		req.SetHost(u.host)
+		err := u.client.DoTimeout(req, res, config.Timeout)
+		if err == nil && res.StatusCode() >= http.StatusOK && res.StatusCode() < http.StatusBadRequest {
+			atomic.StoreInt32(&u.healthy, 1)
+		} else {
+			atomic.StoreInt32(&u.healthy, 0)
+		}
+	}
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+	for {
+		var wg sync.WaitGroup
+		for _, u := range upstreams {
+			wg.Add(1)
+			go func(u *Upstream) {
+				defer wg.Done()
+				check(u)
+			}(u)
+		}
+		wg.Wait()
+		select {
+		case <-ticker.C:
+		case <-config.Stop:
+			return
+		}
+	}
+}
+
+// RoundRobinBalancer picks upstreams in turn
+func RoundRobinBalancer() Balancer {
+	return &roundRobinBalancer{}
+}
+
+type roundRobinBalancer struct {
+	counter uint64
+}
+
+func (b *roundRobinBalancer) Next(_ *slide.Ctx, upstreams []*Upstream) *Upstream {
+	n := atomic.AddUint64(&b.counter, 1)
+	return upstreams[(n-1)%uint64(len(upstreams))]
+}
+
+// LeastConnectionsBalancer picks upstream with fewest requests in flight
+func LeastConnectionsBalancer() Balancer {
+	return leastConnectionsBalancer{}
+}
+
+type leastConnectionsBalancer struct{}
+
+func (leastConnectionsBalancer) Next(_ *slide.Ctx, upstreams []*Upstream) *Upstream {
+	least := upstreams[0]
+	for _, u := range upstreams[1:] {
+		if u.Active() < least.Active() {
+			least = u
+		}
+	}
+	return least
+}
+
+// WeightedBalancer picks upstreams in proportion to their Weight, spread evenly
+// Reference https://github.com/phusion/nginx/commit/27e94984486058d73157038f7950a0a36ecc6e35
+func WeightedBalancer() Balancer {
+	return &weightedBalancer{current: map[*Upstream]int{}}
+}
+
+type weightedBalancer struct {
+	mu      sync.Mutex
+	current map[*Upstream]int
+}
+
+func (b *weightedBalancer) Next(_ *slide.Ctx, upstreams []*Upstream) *Upstream {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var best *Upstream
+	total := 0
+	for _, u := range upstreams {
+		b.current[u] += u.Weight
+		total += u.Weight
+		if best == nil || b.current[u] > b.current[best] {
+			best = u
+		}
+	}
+	b.current[best] -= total
+	return best
+}
@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-slide/slide"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProxySuite struct {
+	suite.Suite
+}
+
+// upstream responding with its name and the request it got
+func newUpstream(name string, h http.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Upstream", name)
+		if h != nil {
+			h(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(name + " " + r.URL.Path))
+	}))
+}
+
+// address nothing listens on
+func deadURL() string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	_ = ln.Close()
+	return "http://" + ln.Addr().String()
+}
+
+func (suite *ProxySuite) do(config ProxyConfig, method, path string, headers map[string]string) (*http.Response, string) {
+	app := slide.InitServer(&slide.Config{})
+	app.Use(Proxy(config))
+	r, err := http.NewRequest(method, "http://test"+path, nil)
+	if !assert.Nil(suite.T(), err) {
+		return nil, ""
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return nil, ""
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res, string(body)
+}
+
+func (suite *ProxySuite) TestForward() {
+	var got http.Header
+	upstream := newUpstream("a", func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		w.Header().Set("Server", "upstream")
+		w.Header().Set(slide.HeaderVary, "Accept-Encoding")
+		w.Header().Set("Connection", "close")
+		w.Header().Add("X-Multi", "1")
+		w.Header().Add("X-Multi", "2")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+	defer upstream.Close()
+	config := ProxyConfig{
+		Upstreams:     []*Upstream{{URL: upstream.URL}},
+		Rewrite:       map[string]string{"/api/*": "/v1/$1"},
+		SetHeaders:    map[string]string{"X-Set": "1"},
+		RemoveHeaders: []string{"X-Remove"},
+	}
+	res, body := suite.do(config, slide.GET, "/api/users", map[string]string{
+		"X-Remove":                "1",
+		"Proxy-Authorization":     "secret",
+		slide.HeaderXForwardedFor: "10.0.0.1",
+	})
+	if res == nil {
+		return
+	}
+	assert.Equal(suite.T(), http.StatusCreated, res.StatusCode)
+	assert.Equal(suite.T(), "/v1/users", body)
+	assert.Equal(suite.T(), "1", got.Get("X-Set"))
+	assert.Empty(suite.T(), got.Get("X-Remove"))
+	assert.Empty(suite.T(), got.Get("Proxy-Authorization"))
+	assert.Equal(suite.T(), "10.0.0.1, 0.0.0.0", got.Get(slide.HeaderXForwardedFor))
+	assert.Equal(suite.T(), "http", got.Get(slide.HeaderXForwardedProto))
+	assert.Equal(suite.T(), "test", got.Get(slide.HeaderXForwardedHost))
+	assert.Equal(suite.T(), []string{"upstream"}, res.Header["Server"])
+	assert.Len(suite.T(), res.Header["Date"], 1)
+	assert.Equal(suite.T(), []string{"1", "2"}, res.Header["X-Multi"])
+	assert.False(suite.T(), res.Close, "hop-by-hop headers are not copied")
+}
+
+func (suite *ProxySuite) TestRewriteQuery() {
+	upstream := newUpstream("a", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.RequestURI()))
+	})
+	defer upstream.Close()
+	config := ProxyConfig{
+		Upstreams: []*Upstream{{URL: upstream.URL}},
+		Rewrite:   map[string]string{"/api/*": "/v1/$1?x=1"},
+	}
+	_, body := suite.do(config, slide.GET, "/api/a", nil)
+	assert.Equal(suite.T(), "/v1/a?x=1", body)
+	_, body = suite.do(config, slide.GET, "/api/a?page=2", nil)
+	assert.Equal(suite.T(), "/v1/a?x=1&page=2", body)
+}
+
+func (suite *ProxySuite) TestVaryMerged() {
+	upstream := newUpstream("a", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(slide.HeaderVary, "Accept-Encoding, Origin")
+	})
+	defer upstream.Close()
+	app := slide.InitServer(&slide.Config{})
+	app.Use(func(ctx *slide.Ctx) error {
+		ctx.Vary("Origin")
+		return ctx.Next()
+	})
+	app.Use(Proxy(ProxyConfig{Upstreams: []*Upstream{{URL: upstream.URL}}}))
+	r, _ := http.NewRequest(slide.GET, "http://test/", nil)
+	res, err := testServer(r, app)
+	if assert.Nil(suite.T(), err) {
+		assert.Equal(suite.T(), []string{"Origin, Accept-Encoding"}, res.Header[slide.HeaderVary])
+	}
+}
+
+func (suite *ProxySuite) TestRetries() {
+	upstream := newUpstream("a", nil)
+	defer upstream.Close()
+	config := ProxyConfig{
+		Upstreams: []*Upstream{{URL: deadURL()}, {URL: upstream.URL}},
+		Retries:   1,
+	}
+	res, body := suite.do(config, slide.GET, "/", nil)
+	if res != nil {
+		assert.Equal(suite.T(), http.StatusOK, res.StatusCode)
+		assert.Equal(suite.T(), "a /", body)
+	}
+	// not idempotent, dead upstream is picked first by a new balancer
+	res, _ = suite.do(config, slide.POST, "/", nil)
+	if res != nil {
+		assert.Equal(suite.T(), http.StatusBadGateway, res.StatusCode)
+	}
+}
+
+func (suite *ProxySuite) TestTimeout() {
+	release := make(chan struct{})
+	upstream := newUpstream("a", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer upstream.Close()
+	defer close(release)
+	config := ProxyConfig{
+		Upstreams: []*Upstream{{URL: upstream.URL}},
+		Timeout:   50 * time.Millisecond,
+	}
+	res, _ := suite.do(config, slide.GET, "/", nil)
+	if res != nil {
+		assert.Equal(suite.T(), http.StatusGatewayTimeout, res.StatusCode)
+	}
+}
+
+func (suite *ProxySuite) TestHealthCheck() {
+	var healthy, checks int32 = 1, 0
+	upstream := newUpstream("a", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			atomic.AddInt32(&checks, 1)
+			if atomic.LoadInt32(&healthy) == 0 {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+	})
+	defer upstream.Close()
+	stop := make(chan struct{})
+	app := slide.InitServer(&slide.Config{})
+	app.Use(Proxy(ProxyConfig{
+		Upstreams:   []*Upstream{{URL: upstream.URL}},
+		HealthCheck: HealthCheckConfig{Path: "/health", Interval: 10 * time.Millisecond, Stop: stop},
+	}))
+	status := func() int {
+		r, _ := http.NewRequest(slide.GET, "http://test/", nil)
+		res, err := testServer(r, app)
+		if !assert.Nil(suite.T(), err) {
+			return 0
+		}
+		return res.StatusCode
+	}
+	assert.Equal(suite.T(), http.StatusOK, status())
+	atomic.StoreInt32(&healthy, 0)
+	assert.Eventually(suite.T(), func() bool {
+		return status() == http.StatusServiceUnavailable
+	}, 5*time.Second, 10*time.Millisecond)
+	atomic.StoreInt32(&healthy, 1)
+	assert.Eventually(suite.T(), func() bool {
+		return status() == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	close(stop)
+	time.Sleep(50 * time.Millisecond)
+	n := atomic.LoadInt32(&checks)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(suite.T(), n, atomic.LoadInt32(&checks), "checks stopped")
+}
+
+func (suite *ProxySuite) TestUpstreamsCopied() {
+	upstream := &Upstream{URL: "http://10.0.0.1:8080"}
+	Proxy(ProxyConfig{Upstreams: []*Upstream{upstream}})
+	assert.Equal(suite.T(), 0, upstream.Weight)
+	assert.Nil(suite.T(), upstream.client)
+	assert.False(suite.T(), upstream.Healthy())
+}
+
+func (suite *ProxySuite) TestInvalidUpstream() {
+	assert.Panics(suite.T(), func() {
+		Proxy(ProxyConfig{})
+	})
+	assert.Panics(suite.T(), func() {
+		Proxy(ProxyConfig{Upstreams: []*Upstream{{URL: "10.0.0.1"}}})
+	})
+}
+
+func TestProxy(t *testing.T) {
+	suite.Run(t, new(ProxySuite))
+}
+
+func upstreamNames(upstreams []*Upstream, picks []*Upstream) string {
+	var s []string
+	for _, p := range picks {
+		for i, u := range upstreams {
+			if u == p {
+				s = append(s, string(rune('a'+i)))
+			}
+		}
+	}
+	return strings.Join(s, "")
+}
+
+func TestBalancers(t *testing.T) {
+	upstreams := []*Upstream{{Weight: 5}, {Weight: 1}, {Weight: 1}}
+	pick := func(b Balancer, n int) string {
+		var picks []*Upstream
+		for i := 0; i < n; i++ {
+			picks = append(picks, b.Next(nil, upstreams))
+		}
+		return upstreamNames(upstreams, picks)
+	}
+	assert.Equal(t, "abcabc", pick(RoundRobinBalancer(), 6))
+	// smooth weighted round robin of nginx
+	assert.Equal(t, "aabacaa", pick(WeightedBalancer(), 7))
+
+	upstreams[0].active = 2
+	upstreams[1].active = 1
+	upstreams[2].active = 3
+	assert.Equal(t, "b", pick(LeastConnectionsBalancer(), 1))
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-slide/slide"
+)
+
+// RedirectConfig configuration for Redirect middleware
+type RedirectConfig struct {
+	// Rules path redirects, "*" matches anything and is referenced with
+	// $1, $2 in the target, ex "/old/*": "/new/$1". Targets can be absolute
+	// urls, ex "/docs/*": "https://docs.example.com/$1"
+	Rules map[string]string
+	// HTTPS redirects plain http requests to https
+	HTTPS bool
+	// AddWWW redirects example.com to www.example.com
+	AddWWW bool
+	// RemoveWWW redirects www.example.com to example.com
+	RemoveWWW bool
+	// Code status code of redirects, defaults to 301. Use 308 to keep
+	// method and body of non GET requests
+	Code int
+}
+
+var (
+	// DefaultRedirectConfig default config for redirect
+	DefaultRedirectConfig = RedirectConfig{
+		Code: http.StatusMovedPermanently,
+	}
+)
+
+// Redirect redirects requests matching the config before routing, scheme,
+// host and path changes are combined into a single redirect and the
+// query of the request is kept
+//
+//	app.Use(middleware.Redirect(middleware.RedirectConfig{
+//		HTTPS:     true,
+//		RemoveWWW: true,
+//		Rules:     map[string]string{"/blog/*": "/articles/$1"},
+//	}))
+func Redirect(config RedirectConfig) func(ctx *slide.Ctx) error {
+	if config.AddWWW && config.RemoveWWW {
+		panic("redirect: AddWWW and RemoveWWW are exclusive")
+	}
+	if config.Code == 0 {
+		config.Code = DefaultRedirectConfig.Code
+	}
+	rules := compileRewriteRules(config.Rules)
+	return func(ctx *slide.Ctx) error {
+		scheme := ctx.Scheme()
+		host := ctx.Host()
+		path := string(ctx.RequestCtx.Path())
+		changed := false
+		if config.HTTPS && scheme == "http" {
+			scheme = "https"
+			changed = true
+		}
+		if config.AddWWW && !strings.HasPrefix(host, "www.") {
+			host = "www." + host
+			changed = true
+		}
+		if config.RemoveWWW && strings.HasPrefix(host, "www.") {
+			host = strings.TrimPrefix(host, "www.")
+			changed = true
+		}
+		if target, ok := rewritePath(rules, path); ok {
+			path = target
+			changed = true
+		}
+		if !changed {
+			return ctx.Next()
+		}
+		target := path
+		if !strings.Contains(path, "://") {
+			target = scheme + "://" + host + path
+		}
+		if query := ctx.RequestCtx.URI().QueryString(); len(query) > 0 && !strings.Contains(target, "?") {
+			target = target + "?" + string(query)
+		}
+		return ctx.Redirect(config.Code, target)
+	}
+}
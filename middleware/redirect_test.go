@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-slide/slide"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirect(t *testing.T) {
+	tests := []struct {
+		config   RedirectConfig
+		url      string
+		proto    string
+		code     int
+		location string
+	}{
+		{RedirectConfig{HTTPS: true}, "http://example.com/a?q=1", "", http.StatusMovedPermanently, "https://example.com/a?q=1"},
+		{RedirectConfig{HTTPS: true}, "http://example.com/a", "https", http.StatusOK, ""},
+		{RedirectConfig{AddWWW: true}, "http://example.com/a", "https", http.StatusMovedPermanently, "https://www.example.com/a"},
+		{RedirectConfig{RemoveWWW: true, Code: http.StatusPermanentRedirect}, "http://www.example.com/a", "", http.StatusPermanentRedirect, "http://example.com/a"},
+		{RedirectConfig{HTTPS: true, RemoveWWW: true, Rules: map[string]string{"/blog/*": "/articles/$1_old"}}, "http://www.example.com/blog/1?q=1", "", http.StatusMovedPermanently, "https://example.com/articles/1_old?q=1"},
+		{RedirectConfig{Rules: map[string]string{"/docs/*": "https://docs.example.com/$1"}}, "http://example.com/docs/intro", "", http.StatusMovedPermanently, "https://docs.example.com/intro"},
+		{RedirectConfig{Rules: map[string]string{"/docs/*": "/guide/$1"}}, "http://example.com/blog", "", http.StatusOK, ""},
+	}
+	for _, test := range tests {
+		app := slide.InitServer(&slide.Config{TrustedProxies: []string{"0.0.0.0"}})
+		app.Use(Redirect(test.config))
+		app.Use(func(ctx *slide.Ctx) error {
+			return ctx.SendStatusCode(http.StatusOK)
+		})
+		r, err := http.NewRequest(slide.GET, test.url, nil)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		if test.proto != "" {
+			r.Header.Set(slide.HeaderXForwardedProto, test.proto)
+		}
+		res, err := testServer(r, app)
+		if assert.Nil(t, err, test.url) {
+			assert.Equal(t, test.code, res.StatusCode, test.url)
+			assert.Equal(t, test.location, res.Header.Get("Location"), test.url)
+		}
+	}
+	assert.Panics(t, func() {
+		Redirect(RedirectConfig{AddWWW: true, RemoveWWW: true})
+	})
+}
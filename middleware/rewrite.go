@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-slide/slide"
+	"github.com/valyala/fasthttp"
+)
+
+// Rewrite rewrites request paths before routing, "*" matches anything and
+// is referenced with $1, $2 in the replacement, longer patterns are tried
+// first. Replacements can carry a query which is added to the request query
+//
+//	app.Use(middleware.Rewrite(map[string]string{
+//		"/old/*":       "/new/$1",
+//		"/users/*/img": "/images?user=$1",
+//	}))
+//
+// Use it with app.Use, group middlewares and routes are resolved
+// with the rewritten path
+func Rewrite(rules map[string]string) func(ctx *slide.Ctx) error {
+	compiled := compileRewriteRules(rules)
+	return func(ctx *slide.Ctx) error {
+		if rewritten, ok := rewritePath(compiled, string(ctx.RequestCtx.Path())); ok {
+			setRewrittenURI(ctx.RequestCtx.URI(), rewritten)
+		}
+		return ctx.Next()
+	}
+}
+
+// sets path and query of rewritten, query of the request is kept
+func setRewrittenURI(uri *fasthttp.URI, rewritten string) {
+	parts := strings.SplitN(rewritten, "?", 2)
+	uri.SetPath(parts[0])
+	if len(parts) == 2 && parts[1] != "" {
+		query := parts[1]
+		if original := uri.QueryString(); len(original) > 0 {
+			query = query + "&" + string(original)
+		}
+		uri.SetQueryString(query)
+	}
+}
+
+type rewriteRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// compiles rules like "/old/*": "/new/$1", longer patterns are tried first
+func compileRewriteRules(rules map[string]string) []rewriteRule {
+	patterns := make([]string, 0, len(rules))
+	for p := range rules {
+		patterns = append(patterns, p)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	compiled := make([]rewriteRule, 0, len(patterns))
+	for _, p := range patterns {
+		expr := "^" + strings.Replace(regexp.QuoteMeta(p), `\*`, "(.*)", -1) + "$"
+		compiled = append(compiled, rewriteRule{pattern: regexp.MustCompile(expr), replacement: replacementTemplate(rules[p])})
+	}
+	return compiled
+}
+
+// converts $1 to ${1} so "/$1_v2" does not reference a group named "1_v2",
+// other $ are kept as is
+func replacementTemplate(replacement string) string {
+	var b strings.Builder
+	for i := 0; i < len(replacement); i++ {
+		if replacement[i] != '$' {
+			b.WriteByte(replacement[i])
+			continue
+		}
+		j := i + 1
+		for j < len(replacement) && replacement[j] >= '0' && replacement[j] <= '9' {
+			j++
+		}
+		if j == i+1 {
+			b.WriteString("$$")
+			continue
+		}
+		b.WriteString("${" + replacement[i+1:j] + "}")
+		i = j - 1
+	}
+	return b.String()
+}
+
+func rewritePath(rules []rewriteRule, path string) (string, bool) {
+	for _, r := range rules {
+		if r.pattern.MatchString(path) {
+			return r.pattern.ReplaceAllString(path, r.replacement), true
+		}
+	}
+	return path, false
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/go-slide/slide"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewrite(t *testing.T) {
+	app := slide.InitServer(&slide.Config{})
+	app.Use(Rewrite(map[string]string{
+		"/old/*":       "/new/$1",
+		"/old/special": "/special",
+		"/api/*":       "/$1_v2",
+		"/users/*/img": "/images?user=$1",
+		"/price/*":     "/cost/$1/$",
+	}))
+	app.Use(func(ctx *slide.Ctx) error {
+		return ctx.Send(http.StatusOK, string(ctx.RequestCtx.URI().RequestURI()))
+	})
+	tests := map[string]string{
+		"/old/a/b":            "/new/a/b",
+		"/old/special":        "/special",
+		"/api/users":          "/users_v2",
+		"/users/42/img":       "/images?user=42",
+		"/users/42/img?s=big": "/images?user=42&s=big",
+		"/price/10":           "/cost/10/$",
+		"/other?q=1":          "/other?q=1",
+	}
+	for path, expected := range tests {
+		r, err := http.NewRequest(slide.GET, "http://test"+path, nil)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		res, err := testServer(r, app)
+		if assert.Nil(t, err, path) {
+			body, _ := ioutil.ReadAll(res.Body)
+			assert.Equal(t, expected, string(body), path)
+		}
+	}
+}
+
+func TestReplacementTemplate(t *testing.T) {
+	assert.Equal(t, "/${1}_v2", replacementTemplate("/$1_v2"))
+	assert.Equal(t, "/${12}/${2}", replacementTemplate("/$12/$2"))
+	assert.Equal(t, "/$$/$$name", replacementTemplate("/$/$name"))
+}
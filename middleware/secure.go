@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-slide/slide"
+)
+
+// CSPNonceSource placeholder source replaced by the nonce of each request,
+//
+//	middleware.NewCSP().Add("script-src", "'self'", middleware.CSPNonceSource)
+const CSPNonceSource = "'nonce'"
+
+// nonce length in bytes
+const cspNonceLength = 16
+
+// CSP content security policy builder
+// Reference https://developer.mozilla.org/en-US/docs/Web/HTTP/CSP
+type CSP struct {
+	directives []string
+	sources    map[string][]string
+}
+
+// NewCSP creates an empty policy
+func NewCSP() *CSP {
+	return &CSP{sources: map[string][]string{}}
+}
+
+// Add appends sources to directive, directives are rendered in the order they were first added
+func (c *CSP) Add(directive string, sources ...string) *CSP {
+	if _, ok := c.sources[directive]; !ok {
+		c.directives = append(c.directives, directive)
+	}
+	c.sources[directive] = append(c.sources[directive], sources...)
+	return c
+}
+
+// String renders the policy
+func (c *CSP) String() string {
+	parts := make([]string, 0, len(c.directives))
+	for _, d := range c.directives {
+		parts = append(parts, strings.TrimSpace(d+" "+strings.Join(c.sources[d], " ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// SecureConfig configuration for Secure middleware,
+// empty fields are not sent, start from DefaultSecureConfig for sensible defaults
+type SecureConfig struct {
+	// HSTSMaxAge Strict-Transport-Security max-age in seconds, only sent
+	// on https requests as seen by ctx.Scheme
+	HSTSMaxAge            int
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	// XContentTypeOptions X-Content-Type-Options
+	XContentTypeOptions string
+	// XFrameOptions X-Frame-Options
+	XFrameOptions string
+	// ReferrerPolicy Referrer-Policy
+	ReferrerPolicy string
+	// PermissionsPolicy Permissions-Policy, ex "geolocation=(), camera=()"
+	PermissionsPolicy string
+	// CrossOriginOpenerPolicy Cross-Origin-Opener-Policy
+	CrossOriginOpenerPolicy string
+	// CrossOriginEmbedderPolicy Cross-Origin-Embedder-Policy
+	CrossOriginEmbedderPolicy string
+	// CrossOriginResourcePolicy Cross-Origin-Resource-Policy
+	CrossOriginResourcePolicy string
+	// ContentSecurityPolicy policy, CSPNonceSource is replaced by a per request nonce
+	// available to handlers and templates with ctx.Nonce()
+	ContentSecurityPolicy *CSP
+	// CSPReportOnly send policy as Content-Security-Policy-Report-Only
+	CSPReportOnly bool
+}
+
+var (
+	// DefaultSecureConfig default config for secure headers
+	DefaultSecureConfig = SecureConfig{
+		HSTSMaxAge:                31536000,
+		HSTSIncludeSubdomains:     true,
+		XContentTypeOptions:       "nosniff",
+		XFrameOptions:             "SAMEORIGIN",
+		ReferrerPolicy:            "strict-origin-when-cross-origin",
+		CrossOriginOpenerPolicy:   "same-origin",
+		CrossOriginResourcePolicy: "same-origin",
+	}
+)
+
+// Secure middleware setting security related response headers
+func Secure(config SecureConfig) func(ctx *slide.Ctx) error {
+	var headers [][2]string
+	hsts := ""
+	add := func(key, value string) {
+		if value != "" {
+			headers = append(headers, [2]string{key, value})
+		}
+	}
+	if config.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d", config.HSTSMaxAge)
+		if config.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if config.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+	add(slide.HeaderXContentTypeOptions, config.XContentTypeOptions)
+	add(slide.HeaderXFrameOptions, config.XFrameOptions)
+	add(slide.HeaderReferrerPolicy, config.ReferrerPolicy)
+	add(slide.HeaderPermissionsPolicy, config.PermissionsPolicy)
+	add(slide.HeaderCrossOriginOpenerPolicy, config.CrossOriginOpenerPolicy)
+	add(slide.HeaderCrossOriginEmbedderPolicy, config.CrossOriginEmbedderPolicy)
+	add(slide.HeaderCrossOriginResourcePolicy, config.CrossOriginResourcePolicy)
+
+	cspHeader := slide.HeaderContentSecurityPolicy
+	if config.CSPReportOnly {
+		cspHeader = slide.HeaderContentSecurityPolicyReportOnly
+	}
+	policy := ""
+	if config.ContentSecurityPolicy != nil {
+		policy = config.ContentSecurityPolicy.String()
+	}
+	useNonce := strings.Contains(policy, CSPNonceSource)
+	if !useNonce {
+		add(cspHeader, policy)
+	}
+	return func(ctx *slide.Ctx) error {
+		for _, h := range headers {
+			ctx.RequestCtx.Response.Header.Set(h[0], h[1])
+		}
+		// must not be sent over plain http, reference https://tools.ietf.org/html/rfc6797#section-7.2
+		if hsts != "" && ctx.Scheme() == "https" {
+			ctx.RequestCtx.Response.Header.Set(slide.HeaderStrictTransportSecurity, hsts)
+		}
+		if useNonce {
+			nonce, err := generateToken(cspNonceLength)
+			if err != nil {
+				return err
+			}
+			ctx.Set(slide.NonceContextKey, nonce)
+			ctx.RequestCtx.Response.Header.Set(cspHeader, strings.Replace(policy, CSPNonceSource, "'nonce-"+nonce+"'", -1))
+		}
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-slide/slide"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SecureSuite struct {
+	suite.Suite
+}
+
+func (suite *SecureSuite) request(config SecureConfig, proto string) (http.Header, string) {
+	// the in-memory client connects from 0.0.0.0
+	app := slide.InitServer(&slide.Config{TrustedProxies: []string{"0.0.0.0"}})
+	app.Use(Secure(config))
+	nonce := ""
+	app.Get("/", func(ctx *slide.Ctx) error {
+		nonce = ctx.Nonce()
+		return ctx.Send(http.StatusOK, "")
+	})
+	r, err := http.NewRequest(slide.GET, "http://test/", nil)
+	if !assert.Nil(suite.T(), err) {
+		return nil, ""
+	}
+	if proto != "" {
+		r.Header.Set(slide.HeaderXForwardedProto, proto)
+	}
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return nil, ""
+	}
+	return res.Header, nonce
+}
+
+func (suite *SecureSuite) TestDefaults() {
+	header, _ := suite.request(DefaultSecureConfig, "https")
+	assert.Equal(suite.T(), "max-age=31536000; includeSubDomains", header.Get(slide.HeaderStrictTransportSecurity))
+	assert.Equal(suite.T(), "nosniff", header.Get(slide.HeaderXContentTypeOptions))
+	assert.Equal(suite.T(), "SAMEORIGIN", header.Get(slide.HeaderXFrameOptions))
+	assert.Equal(suite.T(), "strict-origin-when-cross-origin", header.Get(slide.HeaderReferrerPolicy))
+	assert.Equal(suite.T(), "same-origin", header.Get(slide.HeaderCrossOriginOpenerPolicy))
+	assert.Equal(suite.T(), "same-origin", header.Get(slide.HeaderCrossOriginResourcePolicy))
+	assert.Empty(suite.T(), header.Get(slide.HeaderCrossOriginEmbedderPolicy))
+	assert.Empty(suite.T(), header.Get(slide.HeaderContentSecurityPolicy))
+}
+
+func (suite *SecureSuite) TestHSTSOverHTTP() {
+	header, _ := suite.request(DefaultSecureConfig, "")
+	assert.Empty(suite.T(), header.Get(slide.HeaderStrictTransportSecurity))
+	assert.Equal(suite.T(), "nosniff", header.Get(slide.HeaderXContentTypeOptions))
+}
+
+func (suite *SecureSuite) TestCSP() {
+	config := SecureConfig{
+		ContentSecurityPolicy: NewCSP().Add("default-src", "'self'").Add("script-src", "'self'", CSPNonceSource),
+	}
+	header, nonce := suite.request(config, "")
+	assert.NotEmpty(suite.T(), nonce)
+	assert.Equal(suite.T(), "default-src 'self'; script-src 'self' 'nonce-"+nonce+"'", header.Get(slide.HeaderContentSecurityPolicy))
+	_, other := suite.request(config, "")
+	assert.NotEqual(suite.T(), nonce, other, "nonce of every request is new")
+
+	config = SecureConfig{ContentSecurityPolicy: NewCSP().Add("upgrade-insecure-requests"), CSPReportOnly: true}
+	header, nonce = suite.request(config, "")
+	assert.Empty(suite.T(), nonce)
+	assert.Empty(suite.T(), header.Get(slide.HeaderContentSecurityPolicy))
+	assert.True(suite.T(), strings.HasPrefix(header.Get(slide.HeaderContentSecurityPolicyReportOnly), "upgrade-insecure-requests"))
+}
+
+func TestSecure(t *testing.T) {
+	suite.Run(t, new(SecureSuite))
+}
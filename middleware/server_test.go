@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/go-slide/slide"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// serves req with app over an in-memory listener, the client address
+// seen by the app is 0.0.0.0
+func testServer(req *http.Request, app *slide.Slide) (*http.Response, error) {
+	ln := fasthttputil.NewInmemoryListener()
+	defer ln.Close()
+	go func() {
+		err := fasthttp.Serve(ln, app.Handler())
+		if err != nil {
+			panic(fmt.Errorf("failed to serve: %v", err))
+		}
+	}()
+	client := http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return ln.Dial()
+			},
+		},
+		// redirects are asserted by the tests
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return client.Do(req)
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-slide/slide"
+	"github.com/go-slide/slide/session"
+)
+
+// SessionConfig configuration for Session middleware
+type SessionConfig struct {
+	// Store session storage, defaults to an in-memory store
+	Store session.Store
+	// IdleTimeout session expires when not used for this long, defaults to 30 minutes
+	IdleTimeout time.Duration
+	// AbsoluteTimeout session expires this long after creation regardless of use,
+	// defaults to 24 hours
+	AbsoluteTimeout time.Duration
+	// CookieName name of session id cookie, defaults to "_session"
+	CookieName     string
+	CookieDomain   string
+	CookiePath     string
+	CookieSecure   bool
+	CookieSameSite slide.SameSite
+}
+
+var (
+	// DefaultSessionConfig default config for session
+	DefaultSessionConfig = SessionConfig{
+		IdleTimeout:     30 * time.Minute,
+		AbsoluteTimeout: 24 * time.Hour,
+		CookieName:      "_session",
+		CookiePath:      "/",
+		CookieSameSite:  slide.SameSiteLax,
+	}
+)
+
+// Session middleware, loads session of request before handler and
+// saves it after, session is available with ctx.Session(). New sessions
+// are saved and get a cookie only when the handler stored something
+func Session(config SessionConfig) func(ctx *slide.Ctx) error {
+	if config.Store == nil {
+		config.Store = session.NewMemoryStore()
+	}
+	if config.IdleTimeout == 0 {
+		config.IdleTimeout = DefaultSessionConfig.IdleTimeout
+	}
+	if config.AbsoluteTimeout == 0 {
+		config.AbsoluteTimeout = DefaultSessionConfig.AbsoluteTimeout
+	}
+	if config.CookieName == "" {
+		config.CookieName = DefaultSessionConfig.CookieName
+	}
+	if config.CookiePath == "" {
+		config.CookiePath = DefaultSessionConfig.CookiePath
+	}
+	if config.CookieSameSite == slide.SameSiteDefault {
+		config.CookieSameSite = DefaultSessionConfig.CookieSameSite
+	}
+	return func(ctx *slide.Ctx) error {
+		s, err := loadSession(ctx, &config)
+		if err != nil {
+			return err
+		}
+		ctx.Set(slide.SessionContextKey, s)
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+		return saveSession(ctx, &config, s)
+	}
+}
+
+func loadSession(ctx *slide.Ctx, config *SessionConfig) (*session.Session, error) {
+	now := time.Now()
+	if id := ctx.Cookie(config.CookieName); id != "" {
+		record, err := config.Store.Load(ctx, id)
+		switch {
+		case err == nil:
+			if now.Sub(record.LastAccess) < config.IdleTimeout && now.Sub(record.Created) < config.AbsoluteTimeout {
+				return session.Existing(id, record), nil
+			}
+			if err := config.Store.Delete(ctx, id); err != nil {
+				return nil, err
+			}
+		case err != session.ErrNotFound:
+			return nil, err
+		}
+	}
+	// unknown ids are never reused to prevent session fixation
+	return session.New(&session.Record{
+		Values:  map[string]interface{}{},
+		Created: now,
+	})
+}
+
+func saveSession(ctx *slide.Ctx, config *SessionConfig, s *session.Session) error {
+	// sessions are only started once something is stored, so anonymous
+	// requests neither fill the store nor get a cookie
+	if s.IsNew() && (!s.IsModified() || s.IsDestroyed()) {
+		return nil
+	}
+	if previous := s.Previous(); previous != "" || s.IsDestroyed() {
+		id := previous
+		if id == "" {
+			id = s.ID()
+		}
+		if err := config.Store.Delete(ctx, id); err != nil {
+			return err
+		}
+	}
+	if s.IsDestroyed() {
+		ctx.SetCookie(sessionCookie(config, "", -1))
+		return nil
+	}
+	record := s.Record()
+	record.LastAccess = time.Now()
+	expiry := record.LastAccess.Add(config.IdleTimeout)
+	if absolute := record.Created.Add(config.AbsoluteTimeout); absolute.Before(expiry) {
+		expiry = absolute
+	}
+	if err := config.Store.Save(ctx, s.ID(), record, expiry); err != nil {
+		return err
+	}
+	ctx.SetCookie(sessionCookie(config, s.ID(), int(time.Until(expiry)/time.Second)))
+	return nil
+}
+
+func sessionCookie(config *SessionConfig, id string, maxAge int) *slide.Cookie {
+	return &slide.Cookie{
+		Name:     config.CookieName,
+		Value:    id,
+		Domain:   config.CookieDomain,
+		Path:     config.CookiePath,
+		MaxAge:   maxAge,
+		Secure:   config.CookieSecure,
+		HTTPOnly: true,
+		SameSite: config.CookieSameSite,
+	}
+}
+
+// SessionCSRFStore keeps csrf tokens in the session of request,
+// use it as CSRFConfig.TokenStore with CSRFSynchronizer after Session middleware
+type SessionCSRFStore struct {
+	// Key session key of token, defaults to "_csrf"
+	Key string
+}
+
+func (s SessionCSRFStore) key() string {
+	if s.Key == "" {
+		return "_csrf"
+	}
+	return s.Key
+}
+
+// Load returns token stored in session
+func (s SessionCSRFStore) Load(ctx *slide.Ctx) (string, error) {
+	sess := ctx.Session()
+	if sess == nil {
+		return "", errors.New("csrf: session middleware is required")
+	}
+	token, _ := sess.Get(s.key()).(string)
+	return token, nil
+}
+
+// Save stores token in session
+func (s SessionCSRFStore) Save(ctx *slide.Ctx, token string) error {
+	sess := ctx.Session()
+	if sess == nil {
+		return errors.New("csrf: session middleware is required")
+	}
+	sess.Set(s.key(), token)
+	return nil
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-slide/slide"
+	"github.com/go-slide/slide/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionSuite struct {
+	suite.Suite
+	app   *slide.Slide
+	store *countingSessionStore
+}
+
+// counts saves of the memory store
+type countingSessionStore struct {
+	*session.MemoryStore
+	saves int
+}
+
+func (s *countingSessionStore) Save(ctx *slide.Ctx, id string, record *session.Record, expiry time.Time) error {
+	s.saves++
+	return s.MemoryStore.Save(ctx, id, record, expiry)
+}
+
+func (suite *SessionSuite) setup(appConfig *slide.Config, config SessionConfig) {
+	suite.app = slide.InitServer(appConfig)
+	suite.app.Use(Session(config))
+	suite.app.Get("/", func(ctx *slide.Ctx) error {
+		user, _ := ctx.Session().Get("user").(string)
+		return ctx.Send(http.StatusOK, user)
+	})
+	suite.app.Get("/login", func(ctx *slide.Ctx) error {
+		if err := ctx.Session().Regenerate(); err != nil {
+			return err
+		}
+		ctx.Session().Set("user", ctx.GetQueryParam("user"))
+		return ctx.Send(http.StatusOK, "")
+	})
+	suite.app.Get("/large", func(ctx *slide.Ctx) error {
+		ctx.Session().Set("user", strings.Repeat("a", 5000))
+		return ctx.Send(http.StatusOK, "")
+	})
+	suite.app.Get("/logout", func(ctx *slide.Ctx) error {
+		return ctx.Session().Destroy()
+	})
+}
+
+func (suite *SessionSuite) SetupTest() {
+	suite.store = &countingSessionStore{MemoryStore: session.NewMemoryStore()}
+	suite.setup(&slide.Config{}, SessionConfig{Store: suite.store})
+}
+
+// sends cookies and returns the body and cookies of the response
+func (suite *SessionSuite) request(path string, cookies ...*http.Cookie) (string, map[string]*http.Cookie) {
+	r, err := http.NewRequest(slide.GET, "http://test"+path, nil)
+	if !assert.Nil(suite.T(), err) {
+		return "", nil
+	}
+	for _, c := range cookies {
+		r.AddCookie(c)
+	}
+	res, err := testServer(r, suite.app)
+	if !assert.Nil(suite.T(), err) {
+		return "", nil
+	}
+	assert.Equal(suite.T(), http.StatusOK, res.StatusCode, path)
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	set := map[string]*http.Cookie{}
+	for _, c := range res.Cookies() {
+		set[c.Name] = c
+	}
+	return string(body), set
+}
+
+func (suite *SessionSuite) TestAnonymous() {
+	_, cookies := suite.request("/")
+	assert.Empty(suite.T(), cookies)
+	assert.Equal(suite.T(), 0, suite.store.saves)
+}
+
+func (suite *SessionSuite) TestLifecycle() {
+	_, cookies := suite.request("/login?user=slide")
+	first := cookies["_session"]
+	if !assert.NotNil(suite.T(), first) {
+		return
+	}
+	assert.True(suite.T(), first.HttpOnly)
+
+	body, cookies := suite.request("/", first)
+	assert.Equal(suite.T(), "slide", body)
+	assert.NotNil(suite.T(), cookies["_session"], "idle timeout is extended")
+
+	// regenerated ids replace the old ones
+	_, cookies = suite.request("/login?user=other", first)
+	second := cookies["_session"]
+	if !assert.NotNil(suite.T(), second) {
+		return
+	}
+	assert.NotEqual(suite.T(), first.Value, second.Value)
+	body, _ = suite.request("/", first)
+	assert.Equal(suite.T(), "", body)
+	body, _ = suite.request("/", second)
+	assert.Equal(suite.T(), "other", body)
+
+	_, cookies = suite.request("/logout", second)
+	if assert.NotNil(suite.T(), cookies["_session"]) {
+		assert.True(suite.T(), cookies["_session"].Expires.Before(time.Now()))
+	}
+	body, _ = suite.request("/", second)
+	assert.Equal(suite.T(), "", body)
+}
+
+func (suite *SessionSuite) TestCookieStore() {
+	suite.setup(&slide.Config{
+		CookieEncryptionKeys: [][]byte{[]byte("0123456789abcdef0123456789abcdef")},
+	}, SessionConfig{Store: session.NewCookieStore()})
+	_, cookies := suite.request("/")
+	assert.Empty(suite.T(), cookies)
+
+	_, cookies = suite.request("/login?user=slide")
+	id, data := cookies["_session"], cookies["_session_data"]
+	if !assert.NotNil(suite.T(), id) || !assert.NotNil(suite.T(), data) {
+		return
+	}
+	assert.NotContains(suite.T(), data.Value, "slide", "values are encrypted")
+	body, _ := suite.request("/", id, data)
+	assert.Equal(suite.T(), "slide", body)
+
+	tampered := *data
+	tampered.Value = "x" + tampered.Value[1:]
+	body, _ = suite.request("/", id, &tampered)
+	assert.Equal(suite.T(), "", body)
+
+	_, cookies = suite.request("/logout", id, data)
+	if assert.NotNil(suite.T(), cookies["_session_data"]) {
+		assert.True(suite.T(), cookies["_session_data"].Expires.Before(time.Now()))
+	}
+}
+
+func (suite *SessionSuite) TestCookieStoreTooLarge() {
+	suite.setup(&slide.Config{
+		CookieEncryptionKeys: [][]byte{[]byte("0123456789abcdef")},
+	}, SessionConfig{Store: session.NewCookieStore()})
+	r, err := http.NewRequest(slide.GET, "http://test/large", nil)
+	if !assert.Nil(suite.T(), err) {
+		return
+	}
+	res, err := testServer(r, suite.app)
+	if assert.Nil(suite.T(), err) {
+		assert.Equal(suite.T(), http.StatusInternalServerError, res.StatusCode)
+		for _, c := range res.Cookies() {
+			assert.NotEqual(suite.T(), "_session_data", c.Name)
+		}
+	}
+}
+
+func TestSession(t *testing.T) {
+	suite.Run(t, new(SessionSuite))
+}
@@ -1,9 +1,15 @@
 package slide
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -160,6 +166,277 @@ func (suite *MiddlewareSuite) TestAppLevelMiddlewareMultiError() {
 	}
 }
 
+func (suite *MiddlewareSuite) TestGroupMiddlewareEarlyResponse() {
+	group := suite.Slide.Group("/group")
+	group.Use(func(ctx *Ctx) error {
+		return ctx.Send(http.StatusForbidden, "response from group middleware")
+	})
+	group.Get("/hey", func(ctx *Ctx) error {
+		return ctx.Send(http.StatusOK, "hello, world!")
+	})
+	r, err := http.NewRequest(GET, "http://test/group/hey", nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				suite.T().Error(err)
+			}
+			assert.Equal(suite.T(), res.StatusCode, http.StatusForbidden)
+			assert.Equal(suite.T(), string(body), "response from group middleware")
+		}
+	}
+}
+
+func (suite *MiddlewareSuite) TestNestedGroupMiddlewareOrder() {
+	group := suite.Slide.Group("/group")
+	nested := group.Group("/nested")
+	order := ""
+	nested.Use(func(ctx *Ctx) error {
+		order += "nested,"
+		return ctx.Next()
+	})
+	group.Use(func(ctx *Ctx) error {
+		order += "group,"
+		return ctx.Next()
+	})
+	// /groups is not part of /group
+	suite.Slide.Group("/groups").Use(func(ctx *Ctx) error {
+		order += "groups,"
+		return ctx.Next()
+	})
+	nested.Get("/hey", func(ctx *Ctx) error {
+		order += "handler"
+		return ctx.Send(http.StatusOK, order)
+	})
+	r, err := http.NewRequest(GET, "http://test/group/nested/hey", nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				suite.T().Error(err)
+			}
+			assert.Equal(suite.T(), res.StatusCode, http.StatusOK)
+			assert.Equal(suite.T(), string(body), "group,nested,handler")
+		}
+	}
+}
+
+func (suite *MiddlewareSuite) TestGroupMiddlewareWithoutRoute() {
+	group := suite.Slide.Group("/group")
+	group.Use(func(ctx *Ctx) error {
+		if ctx.RequestCtx.IsOptions() {
+			return ctx.SendStatusCode(http.StatusNoContent)
+		}
+		return ctx.Next()
+	})
+	group.Get("/hey", func(ctx *Ctx) error {
+		return ctx.Send(http.StatusOK, "hello, world!")
+	})
+	r, err := http.NewRequest(OPTIONS, "http://test/group/hey", nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			assert.Equal(suite.T(), res.StatusCode, http.StatusNoContent)
+		}
+	}
+}
+
+type unauthorizedError struct{}
+
+func (unauthorizedError) Error() string {
+	return "unauthorized"
+}
+
+func (unauthorizedError) StatusCode() int {
+	return http.StatusUnauthorized
+}
+
+func (suite *MiddlewareSuite) TestAppLevelMiddlewareStatusError() {
+	path := "/hey"
+	suite.Slide.Use(func(ctx *Ctx) error {
+		return fmt.Errorf("auth: %w", unauthorizedError{})
+	})
+	suite.Slide.Get(path, func(ctx *Ctx) error {
+		return ctx.Send(http.StatusOK, "hello, world!")
+	})
+	r, err := http.NewRequest(GET, "http://test"+path, nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				suite.T().Error(err)
+			}
+			assert.Equal(suite.T(), res.StatusCode, http.StatusUnauthorized)
+			assert.Equal(suite.T(), string(body), "auth: unauthorized")
+		}
+	}
+}
+
+func (suite *MiddlewareSuite) TestBodyLimit() {
+	limit := func(n int64) handler {
+		return func(ctx *Ctx) error {
+			ctx.SetBodyLimit(n)
+			return ctx.Next()
+		}
+	}
+	suite.Slide.Use(limit(4))
+	suite.Slide.Post("/small", func(ctx *Ctx) error {
+		return ctx.SendStatusCode(http.StatusOK)
+	})
+	suite.Slide.Post("/large", func(ctx *Ctx) error {
+		return ctx.SendStatusCode(http.StatusOK)
+	}, limit(16))
+	tests := []struct {
+		path    string
+		body    string
+		chunked bool
+		status  int
+	}{
+		{"/small", "1234", false, http.StatusOK},
+		{"/small", "12345", false, http.StatusRequestEntityTooLarge},
+		{"/large", "12345", false, http.StatusOK},
+		{"/large", "12345678901234567", false, http.StatusRequestEntityTooLarge},
+		{"/small", "1234", true, http.StatusOK},
+		{"/small", "12345", true, http.StatusRequestEntityTooLarge},
+	}
+	for _, test := range tests {
+		r, err := http.NewRequest(POST, "http://test"+test.path, strings.NewReader(test.body))
+		if test.chunked {
+			// unknown length is sent chunked
+			r.ContentLength = -1
+		}
+		if assert.Nil(suite.T(), err) {
+			res, err := testServer(r, suite.Slide)
+			if assert.Nil(suite.T(), err) {
+				assert.Equal(suite.T(), test.status, res.StatusCode, test.path+" "+test.body)
+			}
+		}
+	}
+}
+
+func (suite *MiddlewareSuite) TestBodyLimitBeforeHandler() {
+	limit := func(n int64) handler {
+		return func(ctx *Ctx) error {
+			ctx.SetBodyLimit(n)
+			return ctx.Next()
+		}
+	}
+	dir, err := ioutil.TempDir("", "upload")
+	if !assert.Nil(suite.T(), err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "upload")
+	handler := func(ctx *Ctx) error {
+		return ctx.SendStatusCode(http.StatusOK)
+	}
+	// route middlewares run last to first, the limit is set before them
+	// and they respond without reaching the handler
+	suite.Slide.Post("/bind", handler, func(ctx *Ctx) error {
+		var input map[string]interface{}
+		if err := ctx.Bind(&input); err != nil {
+			return err
+		}
+		return ctx.SendStatusCode(http.StatusAccepted)
+	}, limit(16))
+	suite.Slide.Post("/upload", handler, func(ctx *Ctx) error {
+		if err := ctx.UploadFile(file, "file"); err != nil {
+			return err
+		}
+		return ctx.SendStatusCode(http.StatusAccepted)
+	}, limit(1024))
+	upload := func(size int) (string, string) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "file.txt")
+		assert.Nil(suite.T(), err)
+		_, _ = part.Write(bytes.Repeat([]byte("a"), size))
+		assert.Nil(suite.T(), writer.Close())
+		return body.String(), writer.FormDataContentType()
+	}
+	small, smallType := upload(16)
+	large, largeType := upload(2048)
+	tests := []struct {
+		path        string
+		body        string
+		contentType string
+		status      int
+	}{
+		{"/bind", `{"a":1}`, ApplicationJSON, http.StatusAccepted},
+		{"/bind", `{"a":"12345678901234567890"}`, ApplicationJSON, http.StatusRequestEntityTooLarge},
+		{"/upload", small, smallType, http.StatusAccepted},
+		{"/upload", large, largeType, http.StatusRequestEntityTooLarge},
+	}
+	for _, test := range tests {
+		r, err := http.NewRequest(POST, "http://test"+test.path, strings.NewReader(test.body))
+		if assert.Nil(suite.T(), err) {
+			r.Header.Set(ContentType, test.contentType)
+			res, err := testServer(r, suite.Slide)
+			if assert.Nil(suite.T(), err) {
+				assert.Equal(suite.T(), test.status, res.StatusCode, test.path+" "+test.contentType)
+			}
+		}
+	}
+}
+
+func (suite *MiddlewareSuite) TestStreamedBodyLimit() {
+	// bodies larger than 8 bytes are streamed
+	app := InitServer(&Config{MaxRequestBodySize: 8})
+	limit := func(n int64) handler {
+		return func(ctx *Ctx) error {
+			ctx.SetBodyLimit(n)
+			return ctx.Next()
+		}
+	}
+	echo := func(ctx *Ctx) error {
+		return ctx.Send(http.StatusOK, string(ctx.RequestCtx.Request.Body()))
+	}
+	app.Post("/default", echo)
+	app.Post("/large", echo, limit(32))
+	tests := []struct {
+		path    string
+		body    string
+		chunked bool
+		status  int
+	}{
+		{"/default", "12345678", false, http.StatusOK},
+		{"/default", "123456789", false, http.StatusRequestEntityTooLarge},
+		{"/default", "123456789", true, http.StatusRequestEntityTooLarge},
+		{"/large", "12345678901234567890", false, http.StatusOK},
+		{"/large", "12345678901234567890", true, http.StatusOK},
+		{"/large", strings.Repeat("1", 33), false, http.StatusRequestEntityTooLarge},
+		{"/large", strings.Repeat("1", 33), true, http.StatusRequestEntityTooLarge},
+	}
+	for _, test := range tests {
+		r, err := http.NewRequest(POST, "http://test"+test.path, strings.NewReader(test.body))
+		if test.chunked {
+			r.ContentLength = -1
+		}
+		if assert.Nil(suite.T(), err) {
+			res, err := testServer(r, app)
+			if assert.Nil(suite.T(), err) {
+				body, err := ioutil.ReadAll(res.Body)
+				assert.Nil(suite.T(), err)
+				assert.Equal(suite.T(), test.status, res.StatusCode, test.path+" "+test.body)
+				if test.status == http.StatusOK {
+					assert.Equal(suite.T(), test.body, string(body))
+				}
+			}
+		}
+	}
+}
+
+func TestIsGroupPath(t *testing.T) {
+	assert.Equal(t, true, isGroupPath("/auth", "/auth"))
+	assert.Equal(t, true, isGroupPath("/auth", "/auth/login"))
+	assert.Equal(t, true, isGroupPath("", "/auth/login"))
+	assert.Equal(t, false, isGroupPath("/auth", "/authors"))
+	assert.Equal(t, false, isGroupPath("/auth", "/login/auth"))
+}
+
 func TestMiddleware(t *testing.T) {
 	suite.Run(t, new(MiddlewareSuite))
 }
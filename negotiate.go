@@ -0,0 +1,302 @@
+package slide
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// media types offered by Negotiate in order of preference
+var negotiateOffers = []string{ApplicationJSON, ApplicationXML, ApplicationYAML, ApplicationMsgPack, TextCSV, TextHTML}
+
+// acceptRange value of an Accept-* header with its q value
+type acceptRange struct {
+	value   string
+	quality float64
+}
+
+// Negotiate responds with payload encoded in the media type the client
+// prefers by Accept, one of JSON, XML, YAML, MessagePack, CSV or HTML,
+// JSON when Accept is missing. Returns ErrNotAcceptable when the client
+// accepts none of them
+//
+// XML is not offered for payloads encoding/xml can not encode, like maps.
+// CSV is offered for [][]string and slices of structs, their fields
+// are the columns named by csv tags or field names. HTML is offered
+// for template.HTML only
+func (ctx *Ctx) Negotiate(statusCode int, payload interface{}) error {
+	offers := make([]string, 0, len(negotiateOffers))
+	for _, offer := range negotiateOffers {
+		if (offer == ApplicationXML && !xmlEncodable(payload)) ||
+			(offer == TextCSV && !csvEncodable(payload)) || (offer == TextHTML && !htmlEncodable(payload)) {
+			continue
+		}
+		offers = append(offers, offer)
+	}
+	ctx.Vary(HeaderAccept)
+	switch ctx.Accepts(offers...) {
+	case ApplicationJSON:
+		return ctx.JSON(statusCode, payload)
+	case ApplicationXML:
+		return ctx.XML(statusCode, payload)
+	case ApplicationYAML:
+		return ctx.YAML(statusCode, payload)
+	case ApplicationMsgPack:
+		return ctx.MsgPack(statusCode, payload)
+	case TextCSV:
+		body, err := encodeCSV(payload)
+		if err != nil {
+			return err
+		}
+		return ctx.Blob(statusCode, TextCSV+"; charset=utf-8", body)
+	case TextHTML:
+		return ctx.Blob(statusCode, TextHTML+"; charset=utf-8", []byte(payload.(template.HTML)))
+	}
+	return ErrNotAcceptable
+}
+
+// Accepts returns the offered media type the client prefers by Accept,
+// first offer when Accept is missing, empty when none is acceptable
+//
+//	switch ctx.Accepts("text/html", "application/json") {
+//
+// Ranges like text/* and */* match offers with lower precedence than
+// exact ones, ties of q value go to the earlier offer
+func (ctx *Ctx) Accepts(offers ...string) string {
+	return negotiate(ctx.header(HeaderAccept), offers, matchMediaType, "")
+}
+
+// AcceptsEncodings returns the offered content coding the client prefers
+// by Accept-Encoding, first offer when it is missing, empty when none is
+// acceptable. identity is acceptable unless excluded
+func (ctx *Ctx) AcceptsEncodings(offers ...string) string {
+	return negotiate(ctx.header(HeaderAcceptEncoding), offers, matchEncoding, "identity")
+}
+
+// AcceptsLanguages returns the offered language tag the client prefers by
+// Accept-Language, first offer when it is missing, empty when none is
+// acceptable. Ranges match tags they are a prefix of, en matches en-US
+func (ctx *Ctx) AcceptsLanguages(offers ...string) string {
+	return negotiate(ctx.header(HeaderAcceptLanguage), offers, matchLanguage, "")
+}
+
+func (ctx *Ctx) header(name string) string {
+	return string(ctx.RequestCtx.Request.Header.Peek(name))
+}
+
+// parses ranges of an Accept-* header, parameters other than q are dropped
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		value := strings.ToLower(strings.TrimSpace(fields[0]))
+		if value == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{value: value, quality: q})
+	}
+	return ranges
+}
+
+// returns how specific a range matching offer is, -1 if it does not match
+type matcher func(r, offer string) int
+
+// picks offer with highest q value of its most specific matching range,
+// ties go to the more specific range and then to the earlier offer.
+// implicit offer is acceptable when no range matches it
+func negotiate(header string, offers []string, match matcher, implicit string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	ranges := parseAccept(header)
+	if len(ranges) == 0 {
+		return offers[0]
+	}
+	type candidate struct {
+		offer       string
+		quality     float64
+		specificity int
+	}
+	var candidates []candidate
+	for _, offer := range offers {
+		best := candidate{offer: offer, specificity: -1}
+		for _, r := range ranges {
+			if specificity := match(r.value, strings.ToLower(offer)); specificity > best.specificity {
+				best.quality, best.specificity = r.quality, specificity
+			}
+		}
+		if best.specificity == -1 && implicit != "" && strings.EqualFold(offer, implicit) {
+			// lowest q value, any matching range is preferred
+			best.quality, best.specificity = 0.001, 0
+		}
+		if best.specificity >= 0 && best.quality > 0 {
+			candidates = append(candidates, best)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].quality != candidates[j].quality {
+			return candidates[i].quality > candidates[j].quality
+		}
+		return candidates[i].specificity > candidates[j].specificity
+	})
+	return candidates[0].offer
+}
+
+func matchMediaType(r, offer string) int {
+	offer = mediaType(offer)
+	switch {
+	case r == offer:
+		return 2
+	case r == "*/*":
+		return 0
+	case strings.HasSuffix(r, "/*") && strings.HasPrefix(offer, r[:len(r)-1]):
+		return 1
+	}
+	return -1
+}
+
+func matchEncoding(r, offer string) int {
+	switch r {
+	case offer:
+		return 1
+	case "*":
+		return 0
+	}
+	return -1
+}
+
+func matchLanguage(r, offer string) int {
+	switch {
+	case r == offer:
+		return len(r)
+	case r == "*":
+		return 0
+	case strings.HasPrefix(offer, r+"-"):
+		return len(r)
+	}
+	return -1
+}
+
+var (
+	xmlMarshalerType  = reflect.TypeOf((*xml.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// reports false for types encoding/xml rejects, values of interface
+// fields are only known when encoding so they are assumed encodable
+func xmlEncodable(payload interface{}) bool {
+	return xmlEncodableType(reflect.TypeOf(payload), map[reflect.Type]bool{})
+}
+
+func xmlEncodableType(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t == nil || seen[t] {
+		return true
+	}
+	seen[t] = true
+	if t.Implements(xmlMarshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PtrTo(t).Implements(xmlMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return false
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return xmlEncodableType(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if (field.PkgPath != "" && !field.Anonymous) || field.Tag.Get("xml") == "-" {
+				continue
+			}
+			if !xmlEncodableType(field.Type, seen) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func htmlEncodable(payload interface{}) bool {
+	_, ok := payload.(template.HTML)
+	return ok
+}
+
+func csvEncodable(payload interface{}) bool {
+	if _, ok := payload.([][]string); ok {
+		return true
+	}
+	t := reflect.TypeOf(payload)
+	if t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+		return false
+	}
+	t = t.Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// encodes [][]string as is, slices of structs get a header row
+func encodeCSV(payload interface{}) ([]byte, error) {
+	records, ok := payload.([][]string)
+	if !ok {
+		v := reflect.ValueOf(payload)
+		t := v.Type().Elem()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		var header []string
+		var columns []int
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := field.Tag.Get("csv")
+			if field.PkgPath != "" || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			header = append(header, name)
+			columns = append(columns, i)
+		}
+		records = append(records, header)
+		for i := 0; i < v.Len(); i++ {
+			row := v.Index(i)
+			for row.Kind() == reflect.Ptr && !row.IsNil() {
+				row = row.Elem()
+			}
+			record := make([]string, len(columns))
+			if row.Kind() == reflect.Struct {
+				for j, column := range columns {
+					record[j] = fmt.Sprint(row.Field(column).Interface())
+				}
+			}
+			records = append(records, record)
+		}
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,136 @@
+package slide
+
+import (
+	"encoding/xml"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type NegotiateSuite struct {
+	suite.Suite
+}
+
+type negotiateUser struct {
+	Name  string `json:"name" xml:"name" yaml:"name" csv:"name"`
+	Email string `json:"email" xml:"email" yaml:"email" csv:"email"`
+}
+
+func (suite *NegotiateSuite) request(headers map[string]string, h handler) (int, string, string) {
+	app := InitServer(&Config{})
+	app.Get("/", h)
+	r, err := http.NewRequest(GET, "http://test/", nil)
+	if !assert.Nil(suite.T(), err) {
+		return 0, "", ""
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return 0, "", ""
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res.StatusCode, res.Header.Get(ContentType), string(body)
+}
+
+func (suite *NegotiateSuite) negotiate(accept string, payload interface{}) (int, string, string) {
+	return suite.request(map[string]string{HeaderAccept: accept}, func(ctx *Ctx) error {
+		return ctx.Negotiate(http.StatusOK, payload)
+	})
+}
+
+func (suite *NegotiateSuite) TestNegotiate() {
+	users := []negotiateUser{{Name: "slide", Email: "slide@example.com"}}
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"", ApplicationJSON, `[{"name":"slide","email":"slide@example.com"}]`},
+		{"application/xml;q=0.9, application/json;q=0.8", ApplicationXML, xml.Header + `<negotiateUser><name>slide</name><email>slide@example.com</email></negotiateUser>`},
+		{"application/yaml", ApplicationYAML, "- name: slide\n  email: slide@example.com\n"},
+		{"text/*", "text/csv; charset=utf-8", "name,email\nslide,slide@example.com\n"},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", ApplicationXML, ""},
+	}
+	for _, test := range tests {
+		status, contentType, body := suite.negotiate(test.accept, users)
+		assert.Equal(suite.T(), http.StatusOK, status, test.accept)
+		assert.Equal(suite.T(), test.contentType, contentType, test.accept)
+		if test.body != "" {
+			assert.Equal(suite.T(), test.body, body, test.accept)
+		}
+	}
+}
+
+func (suite *NegotiateSuite) TestHTML() {
+	_, contentType, body := suite.negotiate("text/html,*/*;q=0.8", template.HTML("<p>slide</p>"))
+	assert.Equal(suite.T(), "text/html; charset=utf-8", contentType)
+	assert.Equal(suite.T(), "<p>slide</p>", body)
+
+	// strings are not trusted as HTML
+	_, contentType, _ = suite.negotiate("text/html,*/*;q=0.8", "<p>slide</p>")
+	assert.Equal(suite.T(), ApplicationJSON, contentType)
+}
+
+func (suite *NegotiateSuite) TestXML() {
+	// browsers prefer XML over */*, maps can not be encoded as XML
+	_, contentType, body := suite.negotiate("text/html,application/xml;q=0.9,*/*;q=0.8", map[string]string{"name": "slide"})
+	assert.Equal(suite.T(), ApplicationJSON, contentType)
+	assert.Equal(suite.T(), `{"name":"slide"}`, body)
+	status, _, _ := suite.negotiate("application/xml", map[string]string{"name": "slide"})
+	assert.Equal(suite.T(), http.StatusNotAcceptable, status)
+
+	assert.True(suite.T(), xmlEncodable([]negotiateUser{}))
+	assert.True(suite.T(), xmlEncodable(struct {
+		Name    string
+		Created time.Time
+		Tags    map[string]string `xml:"-"`
+		extra   map[string]string
+	}{}))
+	assert.False(suite.T(), xmlEncodable(struct{ Tags map[string]string }{}))
+	assert.False(suite.T(), xmlEncodable([]map[string]interface{}{}))
+}
+
+func (suite *NegotiateSuite) TestNotAcceptable() {
+	status, _, _ := suite.negotiate("image/png", map[string]string{"name": "slide"})
+	assert.Equal(suite.T(), http.StatusNotAcceptable, status)
+	status, contentType, _ := suite.negotiate("application/json;q=0, */*", negotiateUser{Name: "slide"})
+	assert.Equal(suite.T(), http.StatusOK, status)
+	assert.Equal(suite.T(), ApplicationXML, contentType)
+}
+
+func (suite *NegotiateSuite) TestAccepts() {
+	tests := []struct {
+		headers  map[string]string
+		accepts  func(ctx *Ctx) string
+		expected string
+	}{
+		{nil, func(ctx *Ctx) string { return ctx.Accepts("text/html", "application/json") }, "text/html"},
+		{map[string]string{HeaderAccept: "application/json, text/*;q=0.5"}, func(ctx *Ctx) string { return ctx.Accepts("text/html", "application/json") }, "application/json"},
+		{map[string]string{HeaderAccept: "text/*, text/html;q=0"}, func(ctx *Ctx) string { return ctx.Accepts("text/html") }, ""},
+		{map[string]string{HeaderAcceptEncoding: "gzip;q=0.5, br"}, func(ctx *Ctx) string { return ctx.AcceptsEncodings("gzip", "br") }, "br"},
+		{map[string]string{HeaderAcceptEncoding: "zstd"}, func(ctx *Ctx) string { return ctx.AcceptsEncodings("gzip", "identity") }, "identity"},
+		{map[string]string{HeaderAcceptEncoding: "*;q=0"}, func(ctx *Ctx) string { return ctx.AcceptsEncodings("gzip", "identity") }, ""},
+		{map[string]string{HeaderAcceptLanguage: "fr-CA, en;q=0.8"}, func(ctx *Ctx) string { return ctx.AcceptsLanguages("en-US", "fr") }, "en-US"},
+		{map[string]string{HeaderAcceptLanguage: "fr, en;q=0.8"}, func(ctx *Ctx) string { return ctx.AcceptsLanguages("en-US", "fr-FR") }, "fr-FR"},
+	}
+	for _, test := range tests {
+		var accepted string
+		suite.request(test.headers, func(ctx *Ctx) error {
+			accepted = test.accepts(ctx)
+			return nil
+		})
+		assert.Equal(suite.T(), test.expected, accepted, test.headers)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	suite.Run(t, new(NegotiateSuite))
+}
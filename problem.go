@@ -0,0 +1,213 @@
+package slide
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+// ProblemDetails RFC 9457 problem details document, return it from
+// handlers to respond with a problem of your own
+type ProblemDetails struct {
+	// Type URI of the problem type, "about:blank" when empty
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	// Extensions members added to the document, ex "balance"
+	Extensions map[string]interface{}
+}
+
+func (p *ProblemDetails) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// StatusCode status code to respond with
+func (p *ProblemDetails) StatusCode() int {
+	if p.Status == 0 {
+		return http.StatusInternalServerError
+	}
+	return p.Status
+}
+
+// MarshalJSON writes extension members next to the standard ones,
+// which they can not override
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	members["type"] = p.Type
+	if p.Type == "" {
+		members["type"] = "about:blank"
+	}
+	if p.Title != "" {
+		members["title"] = p.Title
+	}
+	if p.Status != 0 {
+		members["status"] = p.Status
+	}
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// ProblemType problem type of errors matching Err or As
+type ProblemType struct {
+	// Err errors matching it with errors.Is get this problem type
+	//
+	//	{Err: ErrOutOfCredit, Type: "https://example.com/probs/out-of-credit"}
+	Err error
+	// As errors matching its type with errors.As get this problem type,
+	// types matched by Err are tried first
+	//
+	//	{As: &ValidationError{}, Type: "https://example.com/probs/invalid"}
+	As error
+	// Type URI identifying the problem type
+	Type  string
+	Title string
+	// Status overrides status code of the error when set
+	Status int
+}
+
+// ProblemConfig config of Problems
+type ProblemConfig struct {
+	// Types registry of problem types, errors matching none of them
+	// are "about:blank" problems titled with their status text
+	Types []ProblemType
+	// Extensions returns extension members added to every problem,
+	// ex a trace id of the request
+	Extensions func(ctx *Ctx, err error) map[string]interface{}
+}
+
+// Problems returns error handler responding with application/problem+json
+// documents, HTTPError, BindErrors and ValidationError details are
+// added as extension members
+//
+//	app.HandleErrors(slide.Problems(slide.ProblemConfig{
+//		Types: []slide.ProblemType{
+//			{Err: ErrOutOfCredit, Type: "https://example.com/probs/out-of-credit", Title: "You do not have enough credit"},
+//		},
+//	}))
+//
+// Internal causes are never sent and in production neither are details
+// of 5xx errors, both are logged to Config.ErrorLog instead
+func Problems(config ProblemConfig) func(ctx *Ctx, err error) error {
+	return func(ctx *Ctx, err error) error {
+		ctx.app.logHiddenError(ctx, err)
+		problem := config.problem(ctx, err)
+		response, marshalErr := json.Marshal(problem)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		return ctx.Blob(problem.StatusCode(), ApplicationProblemJSON, response)
+	}
+}
+
+func (config *ProblemConfig) problem(ctx *Ctx, err error) *ProblemDetails {
+	production := ctx.app != nil && ctx.app.config.Production
+	var problem ProblemDetails
+	var custom *ProblemDetails
+	if errors.As(err, &custom) {
+		problem = *custom
+	} else {
+		problem.Status = errorStatusCode(err)
+		problem.Detail = problemDetail(err, production)
+		problem.Extensions = problemExtensions(err)
+	}
+	if problemType, ok := config.problemType(err); ok {
+		problem.Type = problemType.Type
+		problem.Title = problemType.Title
+		if problemType.Status != 0 {
+			problem.Status = problemType.Status
+		}
+	}
+	if problem.Status == 0 {
+		problem.Status = http.StatusInternalServerError
+	}
+	if problem.Title == "" && (problem.Type == "" || problem.Type == "about:blank") {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	if problem.Detail == problem.Title {
+		problem.Detail = ""
+	}
+	if problem.Instance == "" {
+		problem.Instance = string(ctx.RequestCtx.Path())
+	}
+	if config.Extensions != nil {
+		extensions := make(map[string]interface{}, len(problem.Extensions))
+		for k, v := range problem.Extensions {
+			extensions[k] = v
+		}
+		for k, v := range config.Extensions(ctx, err) {
+			extensions[k] = v
+		}
+		problem.Extensions = extensions
+	}
+	return &problem
+}
+
+// problem type matching err with errors.Is, then with errors.As
+func (config *ProblemConfig) problemType(err error) (ProblemType, bool) {
+	for _, problemType := range config.Types {
+		if problemType.Err != nil && errors.Is(err, problemType.Err) {
+			return problemType, true
+		}
+	}
+	for _, problemType := range config.Types {
+		if problemType.As == nil {
+			continue
+		}
+		target := reflect.New(reflect.TypeOf(problemType.As))
+		if errors.As(err, target.Interface()) {
+			return problemType, true
+		}
+	}
+	return ProblemType{}, false
+}
+
+func problemDetail(err error, production bool) string {
+	if production && errorStatusCode(err) >= http.StatusInternalServerError {
+		return ""
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Message
+	}
+	return err.Error()
+}
+
+// details of slide errors as extension members
+func problemExtensions(err error) map[string]interface{} {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return map[string]interface{}{"errors": validationErr.Errors}
+	}
+	var bindErrs BindErrors
+	if errors.As(err, &bindErrs) {
+		fields := make([]map[string]string, len(bindErrs))
+		for i, bindErr := range bindErrs {
+			fields[i] = map[string]string{
+				"field":  bindErr.Field,
+				"source": bindErr.Source,
+				"key":    bindErr.Key,
+				"detail": bindErr.Err.Error(),
+			}
+		}
+		return map[string]interface{}{"errors": fields}
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.Details != nil {
+		return map[string]interface{}{"details": httpErr.Details}
+	}
+	return nil
+}
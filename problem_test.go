@@ -0,0 +1,154 @@
+package slide
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var errOutOfCredit = errors.New("out of credit")
+
+type ProblemSuite struct {
+	suite.Suite
+}
+
+func (suite *ProblemSuite) request(config *Config, problemConfig ProblemConfig, h handler, body string) (int, map[string]interface{}) {
+	app := InitServer(config)
+	app.HandleErrors(Problems(problemConfig))
+	app.Post("/accounts/12345", h)
+	r, err := http.NewRequest(POST, "http://test/accounts/12345", strings.NewReader(body))
+	if !assert.Nil(suite.T(), err) {
+		return 0, nil
+	}
+	r.Header.Set(ContentType, ApplicationJSON)
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return 0, nil
+	}
+	assert.Equal(suite.T(), ApplicationProblemJSON, res.Header.Get(ContentType))
+	var problem map[string]interface{}
+	assert.Nil(suite.T(), json.NewDecoder(res.Body).Decode(&problem))
+	return res.StatusCode, problem
+}
+
+func (suite *ProblemSuite) TestHTTPError() {
+	status, problem := suite.request(&Config{}, ProblemConfig{}, func(ctx *Ctx) error {
+		return NewError(http.StatusNotFound, "account not found")
+	}, "")
+	assert.Equal(suite.T(), http.StatusNotFound, status)
+	assert.Equal(suite.T(), map[string]interface{}{
+		"type":     "about:blank",
+		"title":    "Not Found",
+		"status":   float64(404),
+		"detail":   "account not found",
+		"instance": "/accounts/12345",
+	}, problem)
+}
+
+func (suite *ProblemSuite) TestRegisteredType() {
+	config := ProblemConfig{
+		Types: []ProblemType{
+			{Err: errOutOfCredit, Type: "https://example.com/probs/out-of-credit", Title: "You do not have enough credit", Status: http.StatusForbidden},
+		},
+		Extensions: func(ctx *Ctx, err error) map[string]interface{} {
+			return map[string]interface{}{"balance": 30}
+		},
+	}
+	status, problem := suite.request(&Config{}, config, func(ctx *Ctx) error {
+		return fmt.Errorf("transfer: %w", errOutOfCredit)
+	}, "")
+	assert.Equal(suite.T(), http.StatusForbidden, status)
+	assert.Equal(suite.T(), "https://example.com/probs/out-of-credit", problem["type"])
+	assert.Equal(suite.T(), "You do not have enough credit", problem["title"])
+	assert.Equal(suite.T(), "transfer: out of credit", problem["detail"])
+	assert.Equal(suite.T(), float64(30), problem["balance"])
+
+	// errors of the same type do not match Err
+	status, problem = suite.request(&Config{}, config, func(ctx *Ctx) error {
+		return errors.New("insufficient funds")
+	}, "")
+	assert.Equal(suite.T(), http.StatusInternalServerError, status)
+	assert.Equal(suite.T(), "about:blank", problem["type"])
+}
+
+func (suite *ProblemSuite) TestValidationError() {
+	config := ProblemConfig{
+		Types: []ProblemType{
+			{As: &ValidationError{}, Type: "https://example.com/probs/invalid-input", Title: "Invalid input"},
+		},
+	}
+	status, problem := suite.request(&Config{Validator: validator.New()}, config, func(ctx *Ctx) error {
+		return ctx.Bind(&validationInput{})
+	}, `{"name":"slide","age":18}`)
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, status)
+	assert.Equal(suite.T(), "https://example.com/probs/invalid-input", problem["type"])
+	assert.Equal(suite.T(), []interface{}{
+		map[string]interface{}{"field": "address.city", "tag": "required", "message": "City is a required field"},
+	}, problem["errors"])
+}
+
+func (suite *ProblemSuite) TestBindErrors() {
+	status, problem := suite.request(&Config{}, ProblemConfig{}, func(ctx *Ctx) error {
+		return ctx.Bind(&validationInput{})
+	}, `{"age":"18"}`)
+	assert.Equal(suite.T(), http.StatusBadRequest, status)
+	if errs, ok := problem["errors"].([]interface{}); assert.True(suite.T(), ok) && assert.Len(suite.T(), errs, 1) {
+		assert.Equal(suite.T(), "age", errs[0].(map[string]interface{})["field"])
+		assert.Equal(suite.T(), BindBody, errs[0].(map[string]interface{})["source"])
+	}
+}
+
+func (suite *ProblemSuite) TestProduction() {
+	var logs bytes.Buffer
+	config := &Config{Production: true, ErrorLog: log.New(&logs, "", 0)}
+	status, problem := suite.request(config, ProblemConfig{}, func(ctx *Ctx) error {
+		return errors.New("connection refused")
+	}, "")
+	assert.Equal(suite.T(), http.StatusInternalServerError, status)
+	assert.Equal(suite.T(), "Internal Server Error", problem["title"])
+	assert.NotContains(suite.T(), problem, "detail")
+
+	status, problem = suite.request(config, ProblemConfig{}, func(ctx *Ctx) error {
+		return NewError(http.StatusBadGateway, "payments at 10.0.0.5 are down")
+	}, "")
+	assert.Equal(suite.T(), http.StatusBadGateway, status)
+	assert.NotContains(suite.T(), problem, "detail")
+	assert.Contains(suite.T(), logs.String(), "payments at 10.0.0.5 are down")
+}
+
+func (suite *ProblemSuite) TestInternalNotSent() {
+	var logs bytes.Buffer
+	status, problem := suite.request(&Config{ErrorLog: log.New(&logs, "", 0)}, ProblemConfig{}, func(ctx *Ctx) error {
+		return ErrConflict.WithInternal(errors.New("duplicate key"))
+	}, "")
+	assert.Equal(suite.T(), http.StatusConflict, status)
+	assert.NotContains(suite.T(), problem, "detail")
+	assert.Equal(suite.T(), "slide: POST /accounts/12345: Conflict: duplicate key\n", logs.String())
+}
+
+func (suite *ProblemSuite) TestProblemDetails() {
+	status, problem := suite.request(&Config{}, ProblemConfig{}, func(ctx *Ctx) error {
+		return &ProblemDetails{
+			Type:       "https://example.com/probs/locked",
+			Title:      "Account locked",
+			Status:     http.StatusLocked,
+			Extensions: map[string]interface{}{"status": "ignored", "until": "2020-07-01"},
+		}
+	}, "")
+	assert.Equal(suite.T(), http.StatusLocked, status)
+	assert.Equal(suite.T(), float64(http.StatusLocked), problem["status"])
+	assert.Equal(suite.T(), "2020-07-01", problem["until"])
+}
+
+func TestProblem(t *testing.T) {
+	suite.Run(t, new(ProblemSuite))
+}
@@ -0,0 +1,89 @@
+package slide
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+
+	"github.com/vmihailenco/msgpack/v4"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+// JavaScript identifiers, optionally dotted like jQuery.callback
+var jsonpCallbackRegex = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*(\.[a-zA-Z_$][a-zA-Z0-9_$]*)*$`)
+
+// Blob sends body as response with the content type
+func (ctx *Ctx) Blob(statusCode int, contentType string, body []byte) error {
+	ctx.RequestCtx.Response.Header.Set(ContentType, contentType)
+	ctx.RequestCtx.SetStatusCode(statusCode)
+	ctx.RequestCtx.SetBody(body)
+	return nil
+}
+
+// JSONPretty sends payload as JSON indented with indent, ex "  "
+func (ctx *Ctx) JSONPretty(statusCode int, payload interface{}, indent string) error {
+	response, err := ctx.marshalJSON(payload, indent)
+	if err != nil {
+		return err
+	}
+	return ctx.Blob(statusCode, ApplicationJSON, response)
+}
+
+// JSONP sends payload as JSON wrapped in a call of callback, usually
+// read from the callback query param. Callbacks which are not JavaScript
+// identifiers get ErrBadRequest
+func (ctx *Ctx) JSONP(statusCode int, callback string, payload interface{}) error {
+	if !jsonpCallbackRegex.MatchString(callback) {
+		return ErrBadRequest.WithInternal(fmt.Errorf("invalid jsonp callback %q", callback))
+	}
+	response, err := ctx.marshalJSON(payload, "")
+	if err != nil {
+		return err
+	}
+	// leading comment guards against content sniffing attacks
+	body := make([]byte, 0, len(callback)+len(response)+8)
+	body = append(body, "/**/ "...)
+	body = append(body, callback...)
+	body = append(body, '(')
+	body = append(body, response...)
+	body = append(body, ");"...)
+	ctx.RequestCtx.Response.Header.Set(HeaderXContentTypeOptions, "nosniff")
+	return ctx.Blob(statusCode, ApplicationJavaScript+"; charset=utf-8", body)
+}
+
+// XML sends payload as XML with the XML declaration
+func (ctx *Ctx) XML(statusCode int, payload interface{}) error {
+	response, err := xml.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return ctx.Blob(statusCode, ApplicationXML, append([]byte(xml.Header), response...))
+}
+
+// YAML sends payload as YAML
+func (ctx *Ctx) YAML(statusCode int, payload interface{}) error {
+	response, err := yaml.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return ctx.Blob(statusCode, ApplicationYAML, response)
+}
+
+// MsgPack sends payload as MessagePack
+func (ctx *Ctx) MsgPack(statusCode int, payload interface{}) error {
+	response, err := msgpack.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return ctx.Blob(statusCode, ApplicationMsgPack, response)
+}
+
+// ProtoBuf sends message in protobuf wire format
+func (ctx *Ctx) ProtoBuf(statusCode int, message proto.Message) error {
+	response, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return ctx.Blob(statusCode, ApplicationProtobuf, response)
+}
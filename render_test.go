@@ -0,0 +1,86 @@
+package slide
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/vmihailenco/msgpack/v4"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type RenderSuite struct {
+	suite.Suite
+}
+
+func (suite *RenderSuite) render(config *Config, h handler) (*http.Response, string) {
+	app := InitServer(config)
+	app.Get("/", h)
+	r, err := http.NewRequest(GET, "http://test/", nil)
+	if !assert.Nil(suite.T(), err) {
+		return nil, ""
+	}
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return nil, ""
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res, string(body)
+}
+
+func (suite *RenderSuite) TestRenderers() {
+	payload := map[string]string{"name": "slide"}
+	tests := []struct {
+		h           handler
+		contentType string
+		body        string
+	}{
+		{func(ctx *Ctx) error { return ctx.JSONPretty(http.StatusOK, payload, "  ") }, ApplicationJSON, "{\n  \"name\": \"slide\"\n}"},
+		{func(ctx *Ctx) error { return ctx.JSONP(http.StatusOK, "jQuery.cb", payload) }, "application/javascript; charset=utf-8", `/**/ jQuery.cb({"name":"slide"});`},
+		{func(ctx *Ctx) error { return ctx.YAML(http.StatusOK, payload) }, ApplicationYAML, "name: slide\n"},
+		{func(ctx *Ctx) error { return ctx.XML(http.StatusOK, negotiateUser{Name: "slide"}) }, ApplicationXML, xml.Header + "<negotiateUser><name>slide</name><email></email></negotiateUser>"},
+		{func(ctx *Ctx) error { return ctx.Blob(http.StatusOK, "text/plain", []byte("slide")) }, "text/plain", "slide"},
+	}
+	for _, test := range tests {
+		res, body := suite.render(&Config{}, test.h)
+		if res != nil {
+			assert.Equal(suite.T(), http.StatusOK, res.StatusCode)
+			assert.Equal(suite.T(), test.contentType, res.Header.Get(ContentType))
+			assert.Equal(suite.T(), test.body, body)
+		}
+	}
+}
+
+func (suite *RenderSuite) TestBinary() {
+	res, body := suite.render(&Config{}, func(ctx *Ctx) error {
+		return ctx.MsgPack(http.StatusOK, map[string]string{"name": "slide"})
+	})
+	var payload map[string]string
+	assert.Nil(suite.T(), msgpack.Unmarshal([]byte(body), &payload))
+	assert.Equal(suite.T(), "slide", payload["name"])
+	assert.Equal(suite.T(), ApplicationMsgPack, res.Header.Get(ContentType))
+
+	res, body = suite.render(&Config{}, func(ctx *Ctx) error {
+		return ctx.ProtoBuf(http.StatusOK, wrapperspb.String("slide"))
+	})
+	message := &wrapperspb.StringValue{}
+	assert.Nil(suite.T(), proto.Unmarshal([]byte(body), message))
+	assert.Equal(suite.T(), "slide", message.Value)
+	assert.Equal(suite.T(), ApplicationProtobuf, res.Header.Get(ContentType))
+}
+
+func (suite *RenderSuite) TestInvalidJSONPCallback() {
+	res, _ := suite.render(&Config{}, func(ctx *Ctx) error {
+		return ctx.JSONP(http.StatusOK, "alert(1);cb", "slide")
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, res.StatusCode)
+}
+
+func TestRender(t *testing.T) {
+	suite.Run(t, new(RenderSuite))
+}
@@ -1,6 +1,7 @@
 package slide
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -12,6 +13,11 @@ type handler func(ctx *Ctx) error
 // error handler
 type errHandler func(ctx *Ctx, err error) error
 
+// errors carrying their own HTTP status code
+type statusCoder interface {
+	StatusCode() int
+}
+
 type router struct {
 	routerPath string
 	regexPath  string
@@ -26,7 +32,7 @@ type Group struct {
 	middlewareCurrentIndex int
 }
 
-func (g *Group) addRoute(method, path string, h ...handler) {
+func (g *Group) addRoute(method, path string, h []handler) {
 	groupPath := fmt.Sprintf("%s%s", g.path, path)
 	pathWithRegex := findAndReplace(groupPath)
 	g.slide.routerMap[method] = append(g.slide.routerMap[method], router{
@@ -37,25 +43,35 @@ func (g *Group) addRoute(method, path string, h ...handler) {
 }
 
 // Get method of slide
-func (g *Group) Get(path string, h handler) {
+func (g *Group) Get(path string, h ...handler) {
 	g.addRoute(GET, path, h)
 }
 
 // Post method of slide
-func (g *Group) Post(path string, h handler) {
+func (g *Group) Post(path string, h ...handler) {
 	g.addRoute(POST, path, h)
 }
 
 // Put method of slide
-func (g *Group) Put(path string, h handler) {
+func (g *Group) Put(path string, h ...handler) {
 	g.addRoute(PUT, path, h)
 }
 
 // Delete method of slide
-func (g *Group) Delete(path string, h handler) {
+func (g *Group) Delete(path string, h ...handler) {
 	g.addRoute(DELETE, path, h)
 }
 
+// Patch method of slide
+func (g *Group) Patch(path string, h ...handler) {
+	g.addRoute(PATCH, path, h)
+}
+
+// Options method of slide
+func (g *Group) Options(path string, h ...handler) {
+	g.addRoute(OPTIONS, path, h)
+}
+
 // Use -- group level middleware
 func (g *Group) Use(h handler) {
 	g.slide.groupMiddlewareMap[g.path] = append(g.slide.groupMiddlewareMap[g.path], h)
@@ -90,20 +106,23 @@ func handlerRouterError(err error, ctx *Ctx, slide *Slide) {
 		}
 		return
 	}
-	ctx.RequestCtx.Response.SetStatusCode(http.StatusInternalServerError)
-	ctx.RequestCtx.Response.SetBody([]byte(err.Error()))
+	slide.defaultErrorHandler(ctx, err)
 }
 
-func handleRouting(slide *Slide, ctx *Ctx) {
-	// first GET handler by method
-	routesByMethod := slide.routerMap[string(ctx.RequestCtx.Method())]
-	if routesByMethod != nil {
-		groupLevelMiddleware(ctx, slide, routesByMethod)
-	} else {
-		// run 404
-		handle404(slide, ctx)
+// returns status code of error if it has one, 500 otherwise
+func errorStatusCode(err error) int {
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		return sc.StatusCode()
 	}
+	return http.StatusInternalServerError
+}
 
+func handleRouting(slide *Slide, ctx *Ctx) {
+	// group middlewares run for every request under the group,
+	// routes of the method are resolved after them
+	routesByMethod := slide.routerMap[string(ctx.RequestCtx.Method())]
+	groupLevelMiddleware(ctx, slide, routesByMethod)
 }
 
 // calls actual handler
@@ -122,20 +141,32 @@ func handleRouter(ctx *Ctx, slide *Slide, routers []router) {
 		ctx.routerPath = route.routerPath
 		ctx.queryPath = query.String()
 		index := 0
+		// route middlewares come after the handler and run first
+		call := func() error {
+			i := len(route.handlers) - 1 - index
+			if i == 0 {
+				if err := checkBodyLimit(ctx); err != nil {
+					return err
+				}
+			}
+			return route.handlers[i](ctx)
+		}
 		var next func() error
 		next = func() error {
 			index = index + 1
 			if index <= len(route.handlers)-1 {
-				if err := route.handlers[len(route.handlers)-1-index](ctx); err != nil {
+				if err := call(); err != nil {
 					handlerRouterError(err, ctx, slide)
 				}
 			}
 			return nil
 		}
 		ctx.Next = next
-		if err := route.handlers[len(route.handlers)-1-index](ctx); err != nil {
+		if err := call(); err != nil {
 			handlerRouterError(err, ctx, slide)
+			return
 		}
+		applyPreconditions(ctx)
 	} else {
 		handle404(slide, ctx)
 	}
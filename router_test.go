@@ -225,6 +225,35 @@ func (suite *RouterSuit) TestRouteMiddleware() {
 	}
 }
 
+func (suite *RouterSuit) TestPatchAndOptions() {
+	group := suite.Slide.Group("/group")
+	group.Patch("/hey", func(ctx *Ctx) error {
+		return ctx.Send(http.StatusOK, "patched")
+	})
+	suite.Slide.Options("/hey", func(ctx *Ctx) error {
+		return ctx.SendStatusCode(http.StatusNoContent)
+	})
+	r, err := http.NewRequest(PATCH, "http://test/group/hey", nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				suite.T().Error(err)
+			}
+			assert.Equal(suite.T(), res.StatusCode, http.StatusOK)
+			assert.Equal(suite.T(), string(body), "patched")
+		}
+	}
+	r, err = http.NewRequest(OPTIONS, "http://test/hey", nil)
+	if assert.Nil(suite.T(), err) {
+		res, err := testServer(r, suite.Slide)
+		if assert.Nil(suite.T(), err) {
+			assert.Equal(suite.T(), res.StatusCode, http.StatusNoContent)
+		}
+	}
+}
+
 func TestRoutes(t *testing.T) {
 	suite.Run(t, new(RouterSuit))
 }
@@ -2,11 +2,13 @@ package slide
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"strings"
 
+	ut "github.com/go-playground/universal-translator"
 	"github.com/valyala/fasthttp/fasthttputil"
 
 	"github.com/valyala/fasthttp"
@@ -20,6 +22,10 @@ type Slide struct {
 	groupMiddlewareMap map[string][]handler
 	urlNotFoundHandler handler
 	errorHandler       errHandler
+	trustedProxies     []*net.IPNet
+	proxyHeader        string
+	bodyDecoders       map[string]BodyDecoder
+	translator         *ut.UniversalTranslator
 }
 
 // InitServer -- initializing server with slide config
@@ -29,34 +35,65 @@ func InitServer(config *Config) *Slide {
 		routerMap:          map[string][]router{},
 		middleware:         []handler{},
 		groupMiddlewareMap: map[string][]handler{},
+		trustedProxies:     parseTrustedProxies(config.TrustedProxies),
+		proxyHeader:        parseProxyHeader(config.ProxyHeader),
+		bodyDecoders:       bodyDecoders(config),
+		translator:         defaultTranslator(config),
 	}
 }
 
 func requestHandler(c *fasthttp.RequestCtx, slide *Slide) {
 	ctx := getRouterContext(c, slide)
 	appLevelMiddleware(ctx, slide)
+	if c.RequestBodyStream() != nil {
+		// unread body is left on the connection
+		c.SetConnectionClose()
+	}
 }
 
-// Listen -- starting server with given host
-func (slide *Slide) Listen(host string) error {
-	handler := func(c *fasthttp.RequestCtx) {
+// Handler -- fasthttp request handler of the app, use it to serve
+// with a custom fasthttp.Server or to run requests internally
+func (slide *Slide) Handler() fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
 		requestHandler(c, slide)
 	}
+}
+
+// Listen -- starting server with given host
+func (slide *Slide) Listen(host string) error {
+	return slide.newServer().ListenAndServe(host)
+}
+
+// bodies larger than MaxRequestBodySize are streamed, body limits decide
+// how much of them is read
+func (slide *Slide) newServer() *fasthttp.Server {
 	server := &fasthttp.Server{
-		NoDefaultServerHeader: true,
-		Handler:               handler,
+		NoDefaultServerHeader:        true,
+		Handler:                      slide.Handler(),
+		MaxRequestBodySize:           slide.config.MaxRequestBodySize,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 		ErrorHandler: func(r *fasthttp.RequestCtx, err error) {
+			if errors.Is(err, fasthttp.ErrBodyTooLarge) {
+				err = ErrBodyTooLarge
+				r.Response.SetStatusCode(http.StatusRequestEntityTooLarge)
+				r.Response.SetBodyString(err.Error())
+			} else {
+				r.Response.SetStatusCode(http.StatusBadRequest)
+				r.Response.SetBodyString(http.StatusText(http.StatusBadRequest))
+			}
 			if slide.errorHandler != nil {
 				ctx := getRouterContext(r, slide)
 				_ = slide.errorHandler(ctx, err)
 			} else {
-				// TODO replace it with logger
-				fmt.Println(err.Error())
+				slide.logf("slide: %v", err)
 			}
-
 		},
 	}
-	return server.ListenAndServe(host)
+	if slide.config.ErrorLog != nil {
+		server.Logger = slide.config.ErrorLog
+	}
+	return server
 }
 
 func (slide *Slide) addRoute(method, path string, h []handler) {
@@ -93,6 +130,16 @@ func (slide *Slide) Delete(path string, h ...handler) {
 	slide.addRoute(DELETE, path, h)
 }
 
+// Patch method of slide
+func (slide *Slide) Patch(path string, h ...handler) {
+	slide.addRoute(PATCH, path, h)
+}
+
+// Options method of slide
+func (slide *Slide) Options(path string, h ...handler) {
+	slide.addRoute(OPTIONS, path, h)
+}
+
 // Group method
 func (slide *Slide) Group(path string) *Group {
 	return &Group{
@@ -155,10 +202,7 @@ func testServer(req *http.Request, slide *Slide) (*http.Response, error) {
 	ln := fasthttputil.NewInmemoryListener()
 	defer ln.Close()
 	go func() {
-		handler := func(c *fasthttp.RequestCtx) {
-			requestHandler(c, slide)
-		}
-		err := fasthttp.Serve(ln, handler)
+		err := slide.newServer().Serve(ln)
 		if err != nil {
 			panic(fmt.Errorf("failed to serve: %v", err))
 		}
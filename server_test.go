@@ -1,12 +1,15 @@
 package slide
 
 import (
+	"bytes"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"github.com/valyala/fasthttp/fasthttputil"
 )
 
 type ServerSuite struct {
@@ -118,6 +121,28 @@ func (suite *ServerSuite) TestServeFile() {
 	}
 }
 
+func (suite *ServerSuite) TestErrorLog() {
+	var logs bytes.Buffer
+	app := InitServer(&Config{ErrorLog: log.New(&logs, "", 0)})
+	ln := fasthttputil.NewInmemoryListener()
+	defer ln.Close()
+	go func() {
+		_ = app.newServer().Serve(ln)
+	}()
+	conn, err := ln.Dial()
+	if !assert.Nil(suite.T(), err) {
+		return
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nContent-Length: abc\r\n\r\n"))
+	assert.Nil(suite.T(), err)
+	// the connection is closed after the error response
+	res, err := ioutil.ReadAll(conn)
+	assert.Nil(suite.T(), err)
+	assert.Contains(suite.T(), string(res), "400 Bad Request")
+	assert.Contains(suite.T(), logs.String(), "slide: error when reading request headers")
+}
+
 func TestServer(t *testing.T) {
 	suite.Run(t, new(ServerSuite))
 }
@@ -0,0 +1,29 @@
+package slide
+
+// Session -- server side session of current request, see middleware.Session
+type Session interface {
+	// ID returns session id
+	ID() string
+	// Get returns value stored for key, nil if not present
+	Get(key string) interface{}
+	// Set stores value for key
+	Set(key string, value interface{})
+	// Delete removes value for key
+	Delete(key string)
+	// Regenerate issues a new session id keeping the values,
+	// call it after login to prevent session fixation
+	Regenerate() error
+	// Destroy removes the session from store and client
+	Destroy() error
+	// AddFlash adds a message available to next request
+	AddFlash(value interface{})
+	// Flashes returns and removes flash messages
+	Flashes() []interface{}
+}
+
+// Session returns session of current request,
+// nil if session middleware is not used
+func (ctx *Ctx) Session() Session {
+	s, _ := ctx.Get(SessionContextKey).(Session)
+	return s
+}
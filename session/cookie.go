@@ -0,0 +1,82 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-slide/slide"
+)
+
+// browsers drop cookies bigger than 4KB
+const maxCookieSize = 4096
+
+// ErrCookieTooLarge encrypted session does not fit into a cookie
+var ErrCookieTooLarge = errors.New("session is too large for cookie store")
+
+// CookieStore -- keeps the whole session encrypted in a client cookie
+// using slide.Config.CookieEncryptionKeys, nothing is kept on the server
+type CookieStore struct {
+	Name     string
+	Path     string
+	Domain   string
+	Secure   bool
+	SameSite slide.SameSite
+}
+
+// NewCookieStore creates cookie store with default cookie attributes
+func NewCookieStore() *CookieStore {
+	return &CookieStore{
+		Name:     "_session_data",
+		Path:     "/",
+		SameSite: slide.SameSiteLax,
+	}
+}
+
+// Load decrypts record from request cookie, id is not used
+func (s *CookieStore) Load(ctx *slide.Ctx, id string) (*Record, error) {
+	value, err := ctx.EncryptedCookie(s.Name)
+	if err == slide.ErrCookieNotFound || err == slide.ErrInvalidCookie {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeRecord([]byte(value))
+}
+
+// Save encrypts record into response cookie, id is not used
+func (s *CookieStore) Save(ctx *slide.Ctx, id string, record *Record, expiry time.Time) error {
+	data, err := encodeRecord(record, expiry)
+	if err != nil {
+		return err
+	}
+	cookie := &slide.Cookie{
+		Name:     s.Name,
+		Value:    string(data),
+		Path:     s.Path,
+		Domain:   s.Domain,
+		Expires:  expiry,
+		Secure:   s.Secure,
+		HTTPOnly: true,
+		SameSite: s.SameSite,
+	}
+	if err := ctx.SetEncryptedCookie(cookie); err != nil {
+		return err
+	}
+	if len(ctx.RequestCtx.Response.Header.PeekCookie(s.Name)) > maxCookieSize {
+		ctx.RequestCtx.Response.Header.DelCookie(s.Name)
+		return ErrCookieTooLarge
+	}
+	return nil
+}
+
+// Delete expires the cookie on client
+func (s *CookieStore) Delete(ctx *slide.Ctx, id string) error {
+	ctx.SetCookie(&slide.Cookie{
+		Name:   s.Name,
+		Path:   s.Path,
+		Domain: s.Domain,
+		MaxAge: -1,
+	})
+	return nil
+}
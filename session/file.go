@@ -0,0 +1,116 @@
+package session
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-slide/slide"
+)
+
+const fileExtension = ".session"
+
+var errInvalidID = errors.New("invalid session id")
+
+// FileStore -- keeps every session in its own file inside Dir
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore creates store in dir, creating dir if missing
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+// Load reads record of session id
+func (s *FileStore) Load(ctx *slide.Ctx, id string) (*Record, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	record, err := decodeRecord(data)
+	if err == ErrNotFound {
+		_ = os.Remove(path)
+	}
+	return record, err
+}
+
+// Save writes record of session id, file is replaced atomically
+func (s *FileStore) Save(ctx *slide.Ctx, id string, record *Record, expiry time.Time) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	data, err := encodeRecord(record, expiry)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(s.Dir, id+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Delete removes file of session id
+func (s *FileStore) Delete(ctx *slide.Ctx, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Cleanup removes expired session files, run it periodically
+func (s *FileStore) Cleanup() error {
+	files, err := filepath.Glob(filepath.Join(s.Dir, "*"+fileExtension))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		if _, err := decodeRecord(data); err != nil {
+			_ = os.Remove(f)
+		}
+	}
+	return nil
+}
+
+// ids are client controlled, only allow characters of NewID
+// so they can not escape Dir
+func (s *FileStore) path(id string) (string, error) {
+	if id == "" || strings.IndexFunc(id, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+	}) >= 0 {
+		return "", errInvalidID
+	}
+	return filepath.Join(s.Dir, id+fileExtension), nil
+}
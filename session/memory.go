@@ -0,0 +1,72 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-slide/slide"
+)
+
+type memoryEntry struct {
+	record Record
+	expiry time.Time
+}
+
+// MemoryStore -- keeps sessions in process memory,
+// sessions are lost on restart and not shared between instances
+type MemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string]memoryEntry
+	lastGC   time.Time
+	// GCInterval how often expired sessions are removed, defaults to 1 minute
+	GCInterval time.Duration
+}
+
+// NewMemoryStore creates an empty memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions:   map[string]memoryEntry{},
+		lastGC:     time.Now(),
+		GCInterval: time.Minute,
+	}
+}
+
+// Load returns a copy of stored record
+func (s *MemoryStore) Load(ctx *slide.Ctx, id string) (*Record, error) {
+	s.mu.RLock()
+	entry, ok := s.sessions[id]
+	s.mu.RUnlock()
+	if !ok || !time.Now().Before(entry.expiry) {
+		return nil, ErrNotFound
+	}
+	record := entry.record
+	record.Values = copyValues(entry.record.Values)
+	return &record, nil
+}
+
+// Save stores a copy of record
+func (s *MemoryStore) Save(ctx *slide.Ctx, id string, record *Record, expiry time.Time) error {
+	entry := memoryEntry{record: *record, expiry: expiry}
+	entry.record.Values = copyValues(record.Values)
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[id] = entry
+	if now.Sub(s.lastGC) >= s.GCInterval {
+		s.lastGC = now
+		for k, v := range s.sessions {
+			if !now.Before(v.expiry) {
+				delete(s.sessions, k)
+			}
+		}
+	}
+	return nil
+}
+
+// Delete removes session id
+func (s *MemoryStore) Delete(ctx *slide.Ctx, id string) error {
+	s.mu.Lock()
+	delete(s.sessions, id)
+	s.mu.Unlock()
+	return nil
+}
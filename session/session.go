@@ -0,0 +1,140 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+)
+
+const (
+	flashKey = "_flash"
+	idLength = 32
+)
+
+// ErrDestroyed session was destroyed during this request
+var ErrDestroyed = errors.New("session is destroyed")
+
+// Session -- implementation of slide.Session backed by a Record
+type Session struct {
+	id        string
+	previous  string
+	record    *Record
+	isNew     bool
+	modified  bool
+	destroyed bool
+}
+
+// New creates a session with a fresh id for record
+func New(record *Record) (*Session, error) {
+	id, err := NewID()
+	if err != nil {
+		return nil, err
+	}
+	return &Session{id: id, record: record, isNew: true}, nil
+}
+
+// Existing wraps record loaded for id
+func Existing(id string, record *Record) *Session {
+	return &Session{id: id, record: record}
+}
+
+// NewID returns a random url safe session id
+func NewID() (string, error) {
+	b := make([]byte, idLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ID returns session id
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Get returns value stored for key, nil if not present
+func (s *Session) Get(key string) interface{} {
+	return s.record.Values[key]
+}
+
+// Set stores value for key
+func (s *Session) Set(key string, value interface{}) {
+	s.record.Values[key] = value
+	s.modified = true
+}
+
+// Delete removes value for key
+func (s *Session) Delete(key string) {
+	if _, ok := s.record.Values[key]; ok {
+		delete(s.record.Values, key)
+		s.modified = true
+	}
+}
+
+// Regenerate issues a new session id keeping the values,
+// previous id is removed from store when session is saved
+func (s *Session) Regenerate() error {
+	if s.destroyed {
+		return ErrDestroyed
+	}
+	id, err := NewID()
+	if err != nil {
+		return err
+	}
+	if !s.isNew && s.previous == "" {
+		s.previous = s.id
+	}
+	s.id = id
+	s.modified = true
+	return nil
+}
+
+// Destroy marks session to be removed from store and client
+func (s *Session) Destroy() error {
+	s.destroyed = true
+	s.modified = true
+	s.record.Values = map[string]interface{}{}
+	return nil
+}
+
+// AddFlash adds a message available until read with Flashes
+func (s *Session) AddFlash(value interface{}) {
+	flashes, _ := s.record.Values[flashKey].([]interface{})
+	s.record.Values[flashKey] = append(flashes, value)
+	s.modified = true
+}
+
+// Flashes returns and removes flash messages
+func (s *Session) Flashes() []interface{} {
+	flashes, ok := s.record.Values[flashKey].([]interface{})
+	if ok {
+		delete(s.record.Values, flashKey)
+		s.modified = true
+	}
+	return flashes
+}
+
+// Record returns state to persist
+func (s *Session) Record() *Record {
+	return s.record
+}
+
+// Previous returns id replaced by Regenerate, empty if not regenerated
+func (s *Session) Previous() string {
+	return s.previous
+}
+
+// IsNew reports whether the session was created by this request
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+// IsModified reports whether values or the id changed during this request
+func (s *Session) IsModified() bool {
+	return s.modified
+}
+
+// IsDestroyed reports whether Destroy was called
+func (s *Session) IsDestroyed() bool {
+	return s.destroyed
+}
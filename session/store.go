@@ -0,0 +1,73 @@
+package session
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"time"
+
+	"github.com/go-slide/slide"
+)
+
+// ErrNotFound session does not exist or has expired
+var ErrNotFound = errors.New("session not found")
+
+// Record -- persisted state of a session
+type Record struct {
+	Values     map[string]interface{}
+	Created    time.Time
+	LastAccess time.Time
+}
+
+// Store -- session storage used by middleware.Session
+type Store interface {
+	// Load returns record of session id, ErrNotFound if not present or expired
+	Load(ctx *slide.Ctx, id string) (*Record, error)
+	// Save stores record of session id until expiry
+	Save(ctx *slide.Ctx, id string, record *Record, expiry time.Time) error
+	// Delete removes session id
+	Delete(ctx *slide.Ctx, id string) error
+}
+
+func init() {
+	// values are stored as interface{}, flashes as slice of them
+	gob.Register([]interface{}{})
+	gob.Register(map[string]interface{}{})
+}
+
+// file and cookie stores serialize with gob,
+// custom value types have to be registered with gob.Register
+type encodedRecord struct {
+	Record *Record
+	Expiry time.Time
+}
+
+func encodeRecord(record *Record, expiry time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(encodedRecord{Record: record, Expiry: expiry}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeRecord(data []byte) (*Record, error) {
+	var encoded encodedRecord
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&encoded); err != nil {
+		return nil, err
+	}
+	if encoded.Record == nil || !time.Now().Before(encoded.Expiry) {
+		return nil, ErrNotFound
+	}
+	if encoded.Record.Values == nil {
+		encoded.Record.Values = map[string]interface{}{}
+	}
+	return encoded.Record, nil
+}
+
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStore(t *testing.T, store Store) {
+	record := &Record{
+		Values:     map[string]interface{}{"user": "slide", "visits": 2},
+		Created:    time.Now(),
+		LastAccess: time.Now(),
+	}
+	id, err := NewID()
+	assert.Nil(t, err)
+
+	_, err = store.Load(nil, id)
+	assert.Equal(t, ErrNotFound, err)
+
+	assert.Nil(t, store.Save(nil, id, record, time.Now().Add(time.Minute)))
+	loaded, err := store.Load(nil, id)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "slide", loaded.Values["user"])
+		assert.Equal(t, 2, loaded.Values["visits"])
+	}
+
+	assert.Nil(t, store.Delete(nil, id))
+	_, err = store.Load(nil, id)
+	assert.Equal(t, ErrNotFound, err)
+
+	assert.Nil(t, store.Save(nil, id, record, time.Now().Add(-time.Second)))
+	_, err = store.Load(nil, id)
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "slide-session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewFileStore(dir)
+	if assert.Nil(t, err) {
+		testStore(t, store)
+		_, err = store.Load(nil, "../../etc/passwd")
+		assert.Equal(t, ErrNotFound, err)
+	}
+}
+
+func TestSessionFlashesAndRegenerate(t *testing.T) {
+	s := Existing("id", &Record{Values: map[string]interface{}{}})
+	assert.False(t, s.IsNew())
+	s.Delete("missing")
+	assert.Nil(t, s.Flashes())
+	assert.False(t, s.IsModified())
+	s.AddFlash("saved")
+	s.AddFlash("again")
+	assert.Equal(t, []interface{}{"saved", "again"}, s.Flashes())
+	assert.Nil(t, s.Flashes())
+	assert.True(t, s.IsModified())
+
+	assert.Nil(t, s.Regenerate())
+	assert.NotEqual(t, "id", s.ID())
+	assert.Equal(t, "id", s.Previous())
+
+	assert.Nil(t, s.Destroy())
+	assert.True(t, s.IsDestroyed())
+	assert.Equal(t, ErrDestroyed, s.Regenerate())
+}
@@ -0,0 +1,200 @@
+package slide
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event server-sent event, empty fields are left out
+type Event struct {
+	ID    string
+	Event string
+	// Data string and []byte are sent as is, other values as JSON
+	Data interface{}
+	// Retry reconnection time of the client
+	Retry time.Duration
+}
+
+// SSEConfig config of ctx.SSEWithConfig
+type SSEConfig struct {
+	// Heartbeat interval of comments sent to keep idle connections open
+	// and detect disconnects, defaults to 15s, negative disables them.
+	// Disconnects are only noticed when writing, so without heartbeats
+	// Done of an idle stream is not closed until the next Send
+	Heartbeat time.Duration
+	// Retry reconnection time sent to the client when the stream opens
+	Retry time.Duration
+}
+
+// DefaultSSEConfig default config of ctx.SSE
+var DefaultSSEConfig = SSEConfig{
+	Heartbeat: 15 * time.Second,
+}
+
+// ErrStreamClosed event stream was closed or its client disconnected
+var ErrStreamClosed = errors.New("stream closed")
+
+// newlines would end a field and start another one
+var eventFieldReplacer = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// EventStream server-sent events connection of ctx.SSE
+type EventStream struct {
+	ctx         *Ctx
+	w           *bufio.Writer
+	lastEventID string
+	mutex       sync.Mutex
+	done        chan struct{}
+	closed      bool
+}
+
+// SSE streams server-sent events with default config
+func (ctx *Ctx) SSE(fn func(stream *EventStream) error) error {
+	return ctx.SSEWithConfig(DefaultSSEConfig, fn)
+}
+
+// SSEWithConfig responds with a text/event-stream, fn sends events
+// until it returns or the client disconnects
+//
+//	return ctx.SSE(func(stream *slide.EventStream) error {
+//		for progress := range job.Progress(stream.LastEventID()) {
+//			if err := stream.Send(slide.Event{ID: progress.ID, Data: progress}); err != nil {
+//				return err
+//			}
+//		}
+//		return nil
+//	})
+//
+// fn runs after the handler has returned and the response headers are
+// sent, its error only closes the stream. Streams are never compressed,
+// buffered or cached by middlewares
+func (ctx *Ctx) SSEWithConfig(config SSEConfig, fn func(stream *EventStream) error) error {
+	if config.Heartbeat == 0 {
+		config.Heartbeat = DefaultSSEConfig.Heartbeat
+	}
+	header := &ctx.RequestCtx.Response.Header
+	header.Set(ContentType, TextEventStream)
+	header.Set(HeaderCacheControl, "no-cache")
+	// stops nginx from buffering events
+	header.Set(HeaderXAccelBuffering, "no")
+	ctx.RequestCtx.SetStatusCode(http.StatusOK)
+	lastEventID := string(ctx.RequestCtx.Request.Header.Peek(HeaderLastEventID))
+	ctx.RequestCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		stream := &EventStream{ctx: ctx, w: w, lastEventID: lastEventID, done: make(chan struct{})}
+		if config.Retry > 0 {
+			_ = stream.Send(Event{Retry: config.Retry})
+		} else {
+			// sends headers right away
+			_ = stream.write(nil)
+		}
+		stop := make(chan struct{})
+		if config.Heartbeat > 0 {
+			go stream.heartbeat(config.Heartbeat, stop)
+		}
+		_ = fn(stream)
+		close(stop)
+		stream.close()
+	})
+	return nil
+}
+
+// LastEventID returns Last-Event-ID sent by a reconnecting client, resume
+// the stream after it
+func (stream *EventStream) LastEventID() string {
+	return stream.lastEventID
+}
+
+// Done is closed when the client disconnects, which is noticed by the
+// next write, a heartbeat or Send, or when fn returns
+func (stream *EventStream) Done() <-chan struct{} {
+	return stream.done
+}
+
+// Send writes event to the client, fails once it disconnected
+func (stream *EventStream) Send(event Event) error {
+	var buf bytes.Buffer
+	if event.ID != "" {
+		buf.WriteString("id: " + eventFieldReplacer.Replace(event.ID) + "\n")
+	}
+	if event.Event != "" {
+		buf.WriteString("event: " + eventFieldReplacer.Replace(event.Event) + "\n")
+	}
+	if event.Retry > 0 {
+		buf.WriteString("retry: " + strconv.FormatInt(int64(event.Retry/time.Millisecond), 10) + "\n")
+	}
+	if event.Data != nil {
+		var data []byte
+		switch v := event.Data.(type) {
+		case string:
+			data = []byte(v)
+		case []byte:
+			data = v
+		default:
+			var err error
+			if data, err = stream.ctx.marshalJSON(v, ""); err != nil {
+				return err
+			}
+		}
+		// every line of data is a field of its own
+		for _, line := range strings.Split(strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(string(data)), "\n") {
+			buf.WriteString("data: " + line + "\n")
+		}
+	}
+	buf.WriteString("\n")
+	return stream.write(buf.Bytes())
+}
+
+// Comment writes a comment ignored by clients
+func (stream *EventStream) Comment(comment string) error {
+	return stream.write([]byte(": " + eventFieldReplacer.Replace(comment) + "\n\n"))
+}
+
+func (stream *EventStream) write(b []byte) error {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	if stream.closed {
+		return ErrStreamClosed
+	}
+	if _, err := stream.w.Write(b); err != nil {
+		stream.closeLocked()
+		return err
+	}
+	if err := stream.w.Flush(); err != nil {
+		stream.closeLocked()
+		return err
+	}
+	return nil
+}
+
+func (stream *EventStream) heartbeat(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if stream.Comment("heartbeat") != nil {
+				return
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (stream *EventStream) close() {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	stream.closeLocked()
+}
+
+func (stream *EventStream) closeLocked() {
+	if !stream.closed {
+		stream.closed = true
+		close(stream.done)
+	}
+}
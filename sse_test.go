@@ -0,0 +1,115 @@
+package slide
+
+import (
+	"bufio"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SSESuite struct {
+	suite.Suite
+}
+
+func (suite *SSESuite) stream(config SSEConfig, headers map[string]string, fn func(stream *EventStream) error) *http.Response {
+	app := InitServer(&Config{})
+	app.Get("/events", func(ctx *Ctx) error {
+		return ctx.SSEWithConfig(config, fn)
+	})
+	r, err := http.NewRequest(GET, "http://test/events", nil)
+	if !assert.Nil(suite.T(), err) {
+		return nil
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return nil
+	}
+	return res
+}
+
+// reads lines of the next event or comment
+func readEvent(r *bufio.Reader) ([]string, error) {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return lines, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines, nil
+		}
+		lines = append(lines, line)
+	}
+}
+
+func (suite *SSESuite) TestEvents() {
+	next := make(chan struct{})
+	res := suite.stream(SSEConfig{Retry: 3 * time.Second}, map[string]string{HeaderLastEventID: "41"}, func(stream *EventStream) error {
+		if err := stream.Send(Event{ID: "42", Event: "progress", Data: map[string]int{"percent": 50}}); err != nil {
+			return err
+		}
+		// the first event reached the client before the stream ended
+		<-next
+		return stream.Send(Event{ID: "43\nevent: injected", Data: "line 1\nline 2\r\nline " + stream.LastEventID()})
+	})
+	if res == nil {
+		return
+	}
+	defer res.Body.Close()
+	assert.Equal(suite.T(), TextEventStream, res.Header.Get(ContentType))
+	assert.Equal(suite.T(), "no-cache", res.Header.Get(HeaderCacheControl))
+	assert.Equal(suite.T(), "no", res.Header.Get(HeaderXAccelBuffering))
+	r := bufio.NewReader(res.Body)
+	lines, err := readEvent(r)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []string{"retry: 3000"}, lines)
+	lines, err = readEvent(r)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []string{"id: 42", "event: progress", `data: {"percent":50}`}, lines)
+	close(next)
+	lines, err = readEvent(r)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []string{"id: 43 event: injected", "data: line 1", "data: line 2", "data: line 41"}, lines)
+}
+
+func (suite *SSESuite) TestHeartbeatAndDisconnect() {
+	disconnected := make(chan struct{})
+	res := suite.stream(SSEConfig{Heartbeat: 10 * time.Millisecond}, nil, func(stream *EventStream) error {
+		<-stream.Done()
+		assert.Equal(suite.T(), ErrStreamClosed, stream.Send(Event{Data: "late"}))
+		close(disconnected)
+		return nil
+	})
+	if res == nil {
+		return
+	}
+	r := bufio.NewReader(res.Body)
+	for {
+		lines, err := readEvent(r)
+		if !assert.Nil(suite.T(), err) {
+			return
+		}
+		if len(lines) > 0 {
+			assert.Equal(suite.T(), []string{": heartbeat"}, lines)
+			break
+		}
+	}
+	_ = res.Body.Close()
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		suite.T().Error("disconnect was not detected")
+	}
+}
+
+func TestSSE(t *testing.T) {
+	suite.Run(t, new(SSESuite))
+}
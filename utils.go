@@ -8,16 +8,87 @@ import (
 
 // ...
 const (
-	GET    = http.MethodGet
-	POST   = http.MethodPost
-	PUT    = http.MethodPut
-	DELETE = http.MethodDelete
+	GET     = http.MethodGet
+	POST    = http.MethodPost
+	PUT     = http.MethodPut
+	DELETE  = http.MethodDelete
+	PATCH   = http.MethodPatch
+	OPTIONS = http.MethodOptions
 
 	ContentType       = "Content-Type"
 	ContentDeposition = "Content-Disposition"
 	ApplicationJSON   = "application/json"
 	Attachment        = "attachment"
 
+	// media types decoded by Bind
+	ApplicationXML      = "application/xml"
+	TextXML             = "text/xml"
+	ApplicationForm     = "application/x-www-form-urlencoded"
+	MultipartForm       = "multipart/form-data"
+	ApplicationMsgPack  = "application/msgpack"
+	ApplicationCBOR     = "application/cbor"
+	ApplicationYAML     = "application/yaml"
+	ApplicationProtobuf = "application/protobuf"
+
+	// media types of responses
+	TextCSV               = "text/csv"
+	TextHTML              = "text/html"
+	TextEventStream       = "text/event-stream"
+	ApplicationJavaScript = "application/javascript"
+
+	// ApplicationProblemJSON media type of RFC 9457 problem details
+	ApplicationProblemJSON = "application/problem+json"
+
+	HeaderAuthorization   = "Authorization"
+	HeaderAccept          = "Accept"
+	HeaderAcceptEncoding  = "Accept-Encoding"
+	HeaderAcceptLanguage  = "Accept-Language"
+	HeaderContentEncoding = "Content-Encoding"
+	HeaderContentLength   = "Content-Length"
+
+	// conditional request headers
+	HeaderETag              = "ETag"
+	HeaderLastModified      = "Last-Modified"
+	HeaderIfMatch           = "If-Match"
+	HeaderIfNoneMatch       = "If-None-Match"
+	HeaderIfModifiedSince   = "If-Modified-Since"
+	HeaderIfUnmodifiedSince = "If-Unmodified-Since"
+	HeaderCookie            = "Cookie"
+	HeaderSetCookie         = "Set-Cookie"
+	HeaderXCSRFToken        = "X-CSRF-Token"
+
+	// proxy headers
+	HeaderForwarded       = "Forwarded"
+	HeaderXForwardedFor   = "X-Forwarded-For"
+	HeaderXForwardedProto = "X-Forwarded-Proto"
+	HeaderXForwardedHost  = "X-Forwarded-Host"
+	HeaderXRealIP         = "X-Real-IP"
+
+	// caching headers
+	HeaderCacheControl = "Cache-Control"
+	HeaderAge          = "Age"
+
+	// server-sent events
+	HeaderLastEventID     = "Last-Event-ID"
+	HeaderXAccelBuffering = "X-Accel-Buffering"
+
+	// security headers
+	HeaderStrictTransportSecurity         = "Strict-Transport-Security"
+	HeaderXContentTypeOptions             = "X-Content-Type-Options"
+	HeaderXFrameOptions                   = "X-Frame-Options"
+	HeaderReferrerPolicy                  = "Referrer-Policy"
+	HeaderPermissionsPolicy               = "Permissions-Policy"
+	HeaderCrossOriginOpenerPolicy         = "Cross-Origin-Opener-Policy"
+	HeaderCrossOriginEmbedderPolicy       = "Cross-Origin-Embedder-Policy"
+	HeaderCrossOriginResourcePolicy       = "Cross-Origin-Resource-Policy"
+	HeaderContentSecurityPolicy           = "Content-Security-Policy"
+	HeaderContentSecurityPolicyReportOnly = "Content-Security-Policy-Report-Only"
+
+	// keys of values set on Ctx by middlewares
+	CSRFContextKey    = "csrf"
+	SessionContextKey = "session"
+	NonceContextKey   = "nonce"
+
 	// cors headers
 	HeaderOrigin                        = "Origin"
 	HeaderVary                          = "Vary"
@@ -30,6 +101,9 @@ const (
 	HeaderAccessControlAllowHeaders     = "Access-Control-Allow-Headers"
 	HeaderAccessControlMaxAge           = "Access-Control-Max-Age"
 
+	HeaderAccessControlRequestPrivateNetwork = "Access-Control-Request-Private-Network"
+	HeaderAccessControlAllowPrivateNetwork   = "Access-Control-Allow-Private-Network"
+
 	routerRegexReplace = "[a-zA-Z0-9_-]*"
 
 	// routing error messages
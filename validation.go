@@ -0,0 +1,153 @@
+package slide
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+)
+
+// FieldError validation failure of a single field
+type FieldError struct {
+	// Field JSON path of the field, ex "address.city" or "items[0].name"
+	Field string `json:"field"`
+	// Tag validation tag which failed, ex "required" or "min"
+	Tag string `json:"tag"`
+	// Param parameter of the tag, ex "3" for min=3
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError input of ctx.Bind failed Config.Validator, responded
+// with 422 and its fields as JSON by default
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+	err    validator.ValidationErrors
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns errors of the validator
+func (e *ValidationError) Unwrap() error {
+	return e.err
+}
+
+// StatusCode status code to respond with
+func (e *ValidationError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// english messages for the validator of config
+func defaultTranslator(config *Config) *ut.UniversalTranslator {
+	if config.Translator != nil || config.Validator == nil {
+		return config.Translator
+	}
+	english := en.New()
+	translator := ut.New(english, english)
+	trans, _ := translator.GetTranslator("en")
+	if err := entranslations.RegisterDefaultTranslations(config.Validator, trans); err != nil {
+		panic(err)
+	}
+	return translator
+}
+
+// translator for locales of Accept-Language, nil without translations
+func (ctx *Ctx) translator() ut.Translator {
+	if ctx.app == nil || ctx.app.translator == nil {
+		return nil
+	}
+	var locales []string
+	for _, part := range strings.Split(string(ctx.RequestCtx.Request.Header.Peek(HeaderAcceptLanguage)), ",") {
+		locale := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if locale != "" && locale != "*" {
+			// universal translator names locales like pt_BR
+			locales = append(locales, strings.Replace(locale, "-", "_", -1))
+		}
+	}
+	trans, _ := ctx.app.translator.FindTranslator(locales...)
+	return trans
+}
+
+func (ctx *Ctx) validate(input interface{}) error {
+	if ctx.config.Validator == nil {
+		return nil
+	}
+	err := ctx.config.Validator.Struct(input)
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	trans := ctx.translator()
+	validationErr := &ValidationError{err: fieldErrs}
+	for _, fieldErr := range fieldErrs {
+		field := jsonPath(reflect.TypeOf(input), fieldErr.StructNamespace())
+		message := fmt.Sprintf("%s failed on the %s tag", field, fieldErr.Tag())
+		if trans != nil {
+			message = fieldErr.Translate(trans)
+		}
+		validationErr.Errors = append(validationErr.Errors, FieldError{
+			Field:   field,
+			Tag:     fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Message: message,
+		})
+	}
+	return validationErr
+}
+
+// converts struct namespace of the validator like "input.Items[0].Name" to
+// the JSON path "items[0].name", embedded structs are flattened
+func jsonPath(t reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")[1:]
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, index := segment, ""
+		if i := strings.Index(segment, "["); i != -1 {
+			name, index = segment[:i], segment[i:]
+		}
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			path = append(path, segment)
+			t = nil
+			continue
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			path = append(path, segment)
+			t = nil
+			continue
+		}
+		t = field.Type
+		if index != "" {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+				t = t.Elem()
+			}
+		}
+		jsonName := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if field.Anonymous && jsonName == "" {
+			continue
+		}
+		if jsonName == "" || jsonName == "-" {
+			jsonName = field.Name
+		}
+		path = append(path, jsonName+index)
+	}
+	return strings.Join(path, ".")
+}
@@ -0,0 +1,243 @@
+package slide
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/template/parse"
+)
+
+// name of the template layouts render pages with, {{ template "content" . }}
+const contentTemplate = "content"
+
+// ViewEngine renders templates for ctx.Render, set it as Config.Views
+type ViewEngine interface {
+	// Render writes template name executed with data to w, inside layout
+	// when given, "" renders without one. Without layouts the default
+	// layout of the engine is used
+	Render(w io.Writer, name string, data interface{}, layout ...string) error
+}
+
+// HTMLViewsConfig config of HTMLViews
+type HTMLViewsConfig struct {
+	// Dir directory of templates, defaults to "views"
+	Dir string
+	// FileSystem source of templates instead of Dir, use http.FS for
+	// embed.FS, ex http.FS(templates)
+	FileSystem http.FileSystem
+	// Extension of template files, defaults to ".html"
+	Extension string
+	// Layouts glob patterns of layout files relative to Dir, defaults to
+	// "layouts/*". Layouts render the page with {{ template "content" . }}
+	Layouts []string
+	// Partials glob patterns of files shared by all pages, defaults to
+	// "partials/*", include them with {{ template "partials/nav" . }}
+	Partials []string
+	// Layout default layout of pages, ex "layouts/main"
+	Layout string
+	// Funcs functions available in templates
+	Funcs template.FuncMap
+	// Reload parses templates again on every render, use it in development
+	Reload bool
+}
+
+// DefaultHTMLViewsConfig default config of HTMLViews
+var DefaultHTMLViewsConfig = HTMLViewsConfig{
+	Dir:       "views",
+	Extension: ".html",
+	Layouts:   []string{"layouts/*"},
+	Partials:  []string{"partials/*"},
+}
+
+// HTMLViews ViewEngine of html/template
+//
+// Templates are named by their path relative to Dir without the
+// extension, views/users/show.html is "users/show". Every page is parsed
+// with the layouts and partials, its content is the "content" template
+// unless it defines one
+//
+//	views, err := slide.NewHTMLViews(slide.HTMLViewsConfig{Layout: "layouts/main"})
+//	app := slide.InitServer(&slide.Config{Views: views})
+type HTMLViews struct {
+	config HTMLViewsConfig
+	mutex  sync.RWMutex
+	pages  map[string]*template.Template
+}
+
+// NewHTMLViews returns HTMLViews with templates of config loaded
+func NewHTMLViews(config HTMLViewsConfig) (*HTMLViews, error) {
+	if config.FileSystem == nil {
+		if config.Dir == "" {
+			config.Dir = DefaultHTMLViewsConfig.Dir
+		}
+		config.FileSystem = http.Dir(config.Dir)
+	}
+	if config.Extension == "" {
+		config.Extension = DefaultHTMLViewsConfig.Extension
+	}
+	if config.Layouts == nil {
+		config.Layouts = DefaultHTMLViewsConfig.Layouts
+	}
+	if config.Partials == nil {
+		config.Partials = DefaultHTMLViewsConfig.Partials
+	}
+	views := &HTMLViews{config: config}
+	if err := views.Load(); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// Load parses templates again
+func (views *HTMLViews) Load() error {
+	files := map[string]string{}
+	if err := readTemplates(views.config.FileSystem, "/", views.config.Extension, files); err != nil {
+		return fmt.Errorf("views: %w", err)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	// shared templates are parsed in a stable order
+	sort.Strings(names)
+	base := template.New("").Funcs(views.config.Funcs)
+	var pageNames []string
+	for _, name := range names {
+		if !matchAny(views.config.Layouts, name+views.config.Extension) && !matchAny(views.config.Partials, name+views.config.Extension) {
+			pageNames = append(pageNames, name)
+			continue
+		}
+		if _, err := base.New(name).Parse(files[name]); err != nil {
+			return fmt.Errorf("views: %w", err)
+		}
+	}
+	pages := make(map[string]*template.Template, len(pageNames))
+	for _, name := range pageNames {
+		set, err := base.Clone()
+		if err != nil {
+			return fmt.Errorf("views: %w", err)
+		}
+		// layouts can define a default content with {{ block "content" . }}
+		var layoutContent *parse.Tree
+		if content := set.Lookup(contentTemplate); content != nil {
+			layoutContent = content.Tree
+		}
+		page, err := set.New(name).Parse(files[name])
+		if err != nil {
+			return fmt.Errorf("views: %w", err)
+		}
+		// the page is the content unless it defines content itself
+		if content := set.Lookup(contentTemplate); content == nil || content.Tree == layoutContent {
+			if _, err := set.AddParseTree(contentTemplate, page.Tree); err != nil {
+				return fmt.Errorf("views: %w", err)
+			}
+		}
+		pages[name] = set
+	}
+	views.mutex.Lock()
+	views.pages = pages
+	views.mutex.Unlock()
+	return nil
+}
+
+// Render executes page name, inside layout or the default layout
+func (views *HTMLViews) Render(w io.Writer, name string, data interface{}, layout ...string) error {
+	if views.config.Reload {
+		if err := views.Load(); err != nil {
+			return err
+		}
+	}
+	views.mutex.RLock()
+	page, ok := views.pages[name]
+	views.mutex.RUnlock()
+	if !ok {
+		return fmt.Errorf("views: template %q not found", name)
+	}
+	current := views.config.Layout
+	if len(layout) > 0 {
+		current = layout[0]
+	}
+	if current == "" {
+		return page.ExecuteTemplate(w, contentTemplate, data)
+	}
+	return page.ExecuteTemplate(w, current, data)
+}
+
+// reads files with extension under dir of fs, keyed by name
+func readTemplates(fs http.FileSystem, dir, extension string, files map[string]string) error {
+	f, err := fs.Open(dir)
+	if err != nil {
+		return err
+	}
+	infos, err := f.Readdir(-1)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		filePath := path.Join(dir, info.Name())
+		if info.IsDir() {
+			if err := readTemplates(fs, filePath, extension, files); err != nil {
+				return err
+			}
+			continue
+		}
+		if !strings.HasSuffix(info.Name(), extension) {
+			continue
+		}
+		file, err := fs.Open(filePath)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadAll(file)
+		_ = file.Close()
+		if err != nil {
+			return err
+		}
+		files[strings.TrimSuffix(strings.TrimPrefix(filePath, "/"), extension)] = string(content)
+	}
+	return nil
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Render responds with template name of Config.Views as HTML, in layout
+// when given. Maps and nil data get CSRFToken and Nonce of the request
+// unless they have them, for forms and inline scripts
+//
+//	<input type="hidden" name="_csrf" value="{{ .CSRFToken }}">
+//	<script nonce="{{ .Nonce }}">
+func (ctx *Ctx) Render(statusCode int, name string, data interface{}, layout ...string) error {
+	if ctx.config.Views == nil {
+		return errors.New("views: Config.Views is not set")
+	}
+	if values, ok := data.(map[string]interface{}); ok || data == nil {
+		withRequest := make(map[string]interface{}, len(values)+2)
+		withRequest["CSRFToken"] = ctx.CSRFToken()
+		withRequest["Nonce"] = ctx.Nonce()
+		for k, v := range values {
+			withRequest[k] = v
+		}
+		data = withRequest
+	}
+	var buf bytes.Buffer
+	if err := ctx.config.Views.Render(&buf, name, data, layout...); err != nil {
+		return err
+	}
+	return ctx.Blob(statusCode, TextHTML+"; charset=utf-8", buf.Bytes())
+}
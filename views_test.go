@@ -0,0 +1,159 @@
+package slide
+
+import (
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ViewsSuite struct {
+	suite.Suite
+	dir string
+}
+
+var viewFiles = map[string]string{
+	"layouts/main.html":  `<html>{{ template "partials/nav" . }}<main>{{ template "content" . }}</main></html>`,
+	"partials/nav.html":  `<nav>{{ upper .Title }}</nav>`,
+	"users/show.html":    `<p>{{ .Name }}</p><script nonce="{{ .Nonce }}"></script>`,
+	"users/form.html":    `{{ define "content" }}<input name="_csrf" value="{{ .CSRFToken }}">{{ end }}`,
+	"ignored/readme.txt": `not a template`,
+}
+
+func (suite *ViewsSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "views")
+	if !assert.Nil(suite.T(), err) {
+		return
+	}
+	suite.dir = dir
+	for name, content := range viewFiles {
+		suite.write(name, content)
+	}
+}
+
+func (suite *ViewsSuite) TearDownTest() {
+	_ = os.RemoveAll(suite.dir)
+}
+
+func (suite *ViewsSuite) write(name, content string) {
+	file := filepath.Join(suite.dir, filepath.FromSlash(name))
+	assert.Nil(suite.T(), os.MkdirAll(filepath.Dir(file), 0755))
+	assert.Nil(suite.T(), ioutil.WriteFile(file, []byte(content), 0644))
+}
+
+func (suite *ViewsSuite) views(config HTMLViewsConfig) *HTMLViews {
+	config.Dir = suite.dir
+	config.Funcs = template.FuncMap{"upper": strings.ToUpper}
+	views, err := NewHTMLViews(config)
+	assert.Nil(suite.T(), err)
+	return views
+}
+
+func (suite *ViewsSuite) render(views ViewEngine, h handler) (int, string, string) {
+	app := InitServer(&Config{Views: views})
+	app.Get("/", func(ctx *Ctx) error {
+		ctx.Set(CSRFContextKey, "token")
+		ctx.Set(NonceContextKey, "nonce")
+		return h(ctx)
+	})
+	r, err := http.NewRequest(GET, "http://test/", nil)
+	if !assert.Nil(suite.T(), err) {
+		return 0, "", ""
+	}
+	res, err := testServer(r, app)
+	if !assert.Nil(suite.T(), err) {
+		return 0, "", ""
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(suite.T(), err)
+	return res.StatusCode, res.Header.Get(ContentType), string(body)
+}
+
+func (suite *ViewsSuite) TestLayout() {
+	views := suite.views(HTMLViewsConfig{Layout: "layouts/main"})
+	status, contentType, body := suite.render(views, func(ctx *Ctx) error {
+		return ctx.Render(http.StatusOK, "users/show", map[string]interface{}{"Title": "users", "Name": "<slide>"})
+	})
+	assert.Equal(suite.T(), http.StatusOK, status)
+	assert.Equal(suite.T(), "text/html; charset=utf-8", contentType)
+	assert.Equal(suite.T(), `<html><nav>USERS</nav><main><p>&lt;slide&gt;</p><script nonce="nonce"></script></main></html>`, body)
+}
+
+func (suite *ViewsSuite) TestBlockLayout() {
+	suite.write("layouts/block.html", `<html><main>{{ block "content" . }}default{{ end }}</main></html>`)
+	views := suite.views(HTMLViewsConfig{Layout: "layouts/block"})
+	_, _, body := suite.render(views, func(ctx *Ctx) error {
+		return ctx.Render(http.StatusOK, "users/show", map[string]interface{}{"Name": "slide"})
+	})
+	assert.Equal(suite.T(), `<html><main><p>slide</p><script nonce="nonce"></script></main></html>`, body)
+	_, _, body = suite.render(views, func(ctx *Ctx) error {
+		return ctx.Render(http.StatusOK, "users/form", nil)
+	})
+	assert.Equal(suite.T(), `<html><main><input name="_csrf" value="token"></main></html>`, body)
+}
+
+func (suite *ViewsSuite) TestWithoutLayout() {
+	views := suite.views(HTMLViewsConfig{Layout: "layouts/main"})
+	_, _, body := suite.render(views, func(ctx *Ctx) error {
+		return ctx.Render(http.StatusOK, "users/form", nil, "")
+	})
+	assert.Equal(suite.T(), `<input name="_csrf" value="token">`, body)
+}
+
+func (suite *ViewsSuite) TestStructData() {
+	views := suite.views(HTMLViewsConfig{})
+	_, _, body := suite.render(views, func(ctx *Ctx) error {
+		return ctx.Render(http.StatusOK, "users/show", struct{ Name, Nonce string }{"slide", "own"})
+	})
+	assert.Equal(suite.T(), `<p>slide</p><script nonce="own"></script>`, body)
+}
+
+func (suite *ViewsSuite) TestFileSystem() {
+	views, err := NewHTMLViews(HTMLViewsConfig{
+		FileSystem: http.Dir(suite.dir),
+		Layouts:    []string{},
+		Partials:   []string{"partials/*", "layouts/*"},
+		Funcs:      template.FuncMap{"upper": strings.ToUpper},
+	})
+	if assert.Nil(suite.T(), err) {
+		_, _, body := suite.render(views, func(ctx *Ctx) error {
+			return ctx.Render(http.StatusOK, "users/show", map[string]interface{}{"Title": "users", "Name": "slide"}, "layouts/main")
+		})
+		assert.Contains(suite.T(), body, "<p>slide</p>")
+	}
+}
+
+func (suite *ViewsSuite) TestReload() {
+	views := suite.views(HTMLViewsConfig{Reload: true})
+	suite.write("users/show.html", `<p>changed {{ .Name }}</p>`)
+	_, _, body := suite.render(views, func(ctx *Ctx) error {
+		return ctx.Render(http.StatusOK, "users/show", map[string]interface{}{"Name": "slide"})
+	})
+	assert.Equal(suite.T(), `<p>changed slide</p>`, body)
+}
+
+func (suite *ViewsSuite) TestErrors() {
+	views := suite.views(HTMLViewsConfig{})
+	status, _, _ := suite.render(views, func(ctx *Ctx) error {
+		return ctx.Render(http.StatusOK, "users/missing", nil)
+	})
+	assert.Equal(suite.T(), http.StatusInternalServerError, status)
+	status, _, _ = suite.render(nil, func(ctx *Ctx) error {
+		return ctx.Render(http.StatusOK, "users/show", nil)
+	})
+	assert.Equal(suite.T(), http.StatusInternalServerError, status)
+
+	suite.write("users/broken.html", `{{ .Name `)
+	_, err := NewHTMLViews(HTMLViewsConfig{Dir: suite.dir})
+	assert.NotNil(suite.T(), err)
+}
+
+func TestViews(t *testing.T) {
+	suite.Run(t, new(ViewsSuite))
+}